@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"github.com/henrylee2cn/goutil"
+
+	"github.com/henrylee2cn/rester"
+)
+
+// APIKeyLookup looks up the principal of the API key, such as from the database,
+// returns nil principal if the key is not found or revoked, or an error if the lookup cannot be done.
+type APIKeyLookup func(key string) (*Principal, error)
+
+// APIKeyCtl the middleware controller of the API key in the request header, e.g.
+//   api := engine.Group("/api", auth.NewAPIKeyCtl("", lookup))
+type APIKeyCtl struct {
+	rester.BaseCtl
+	header string
+	lookup APIKeyLookup
+}
+
+// NewAPIKeyCtl creates the middleware controller of the API key,
+// the header uses 'X-API-Key' by default when empty.
+func NewAPIKeyCtl(header string, lookup APIKeyLookup) *APIKeyCtl {
+	goutil.InitAndGetString(&header, "X-API-Key")
+	return &APIKeyCtl{header: header, lookup: lookup}
+}
+
+// Any authenticates the request, and aborts with 401 Unauthorized if it fails.
+func (ctl *APIKeyCtl) Any() error {
+	key := string(ctl.Request.Header.Peek(ctl.header))
+	if key == "" {
+		unauthorized(&ctl.BaseCtl, "", "missing API key")
+		return nil
+	}
+	p, err := ctl.lookup(key)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		unauthorized(&ctl.BaseCtl, "", "invalid API key")
+		return nil
+	}
+	p.Scheme = "APIKey"
+	SetPrincipal(ctl.RequestCtx, p)
+	return nil
+}
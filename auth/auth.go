@@ -0,0 +1,85 @@
+// Package auth the middleware controllers of the authentication, such as Basic, Bearer JWT and API keys,
+// which store the authenticated principal of the request, or abort the chain with 401 Unauthorized,
+// and the RBAC and ABAC policy authorizing the principal.
+//
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package auth
+
+import (
+	"strings"
+
+	"github.com/henrylee2cn/ameda"
+	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester"
+)
+
+// Principal the authenticated user or client of the request
+type Principal struct {
+	// Subject the user name, the 'sub' claim of JWT, or the owner of the API key
+	Subject string
+	// Scheme the authentication scheme, 'Basic', 'Bearer' or 'APIKey'
+	Scheme string
+	// Roles the roles granted to the principal
+	Roles []string
+	// Claims the claims of JWT, or the custom attributes set by the validator
+	Claims map[string]interface{}
+}
+
+// Has returns true if the principal has the role.
+func (p *Principal) Has(role string) bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+const principalUserValueKey = "__rester_principal__"
+
+// SetPrincipal sets the authenticated principal of the request.
+func SetPrincipal(ctx *rester.RequestCtx, p *Principal) {
+	ctx.SetUserValue(principalUserValueKey, p)
+}
+
+// GetPrincipal returns the authenticated principal of the request, or nil if it is not authenticated.
+func GetPrincipal(ctx *rester.RequestCtx) *Principal {
+	p, _ := ctx.UserValue(principalUserValueKey).(*Principal)
+	return p
+}
+
+// authorization returns the credentials of the Authorization header with the scheme,
+// the scheme is case-insensitive.
+func authorization(ctx *rester.RequestCtx, scheme string) (string, bool) {
+	v := ameda.UnsafeBytesToString(ctx.Request.Header.Peek("Authorization"))
+	if len(v) <= len(scheme) || v[len(scheme)] != ' ' || !strings.EqualFold(v[:len(scheme)], scheme) {
+		return "", false
+	}
+	credentials := strings.TrimSpace(v[len(scheme)+1:])
+	return credentials, credentials != ""
+}
+
+// unauthorized sets the WWW-Authenticate header if challenge is not empty,
+// and aborts the chain with 401 Unauthorized.
+func unauthorized(ctl *rester.BaseCtl, challenge, msg string) {
+	if challenge != "" {
+		ctl.Response.Header.Set("WWW-Authenticate", challenge)
+	}
+	ctl.Unauthorized(fasthttp.StatusUnauthorized, msg)
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester"
+)
+
+type BasicUserCtl struct {
+	BasicCtl
+}
+
+func (ctl *BasicUserCtl) GET() {
+	ctl.SetUserValue("user", GetPrincipal(ctl.RequestCtx))
+}
+
+type APIKeyUserCtl struct {
+	APIKeyCtl
+}
+
+func (ctl *APIKeyUserCtl) GET() {
+	ctl.SetUserValue("user", GetPrincipal(ctl.RequestCtx))
+}
+
+type JWTUserCtl struct {
+	JWTCtl
+}
+
+func (ctl *JWTUserCtl) GET() {
+	ctl.SetUserValue("user", GetPrincipal(ctl.RequestCtx))
+}
+
+func serve(handlers map[string]rester.RequestHandler, header, value string) *fasthttp.RequestCtx {
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Init(new(fasthttp.Request), nil, nil)
+	if value != "" {
+		ctx.Request.Header.Set(header, value)
+	}
+	handlers["GET"](ctx)
+	return ctx
+}
+
+func TestBasicCtl(t *testing.T) {
+	mw := NewBasicCtl("admin", BasicAccounts(map[string]string{"henry": "s3cret"}))
+	handlers := rester.MustMakeHandlers(func() rester.Controller {
+		return &BasicUserCtl{BasicCtl: *mw}
+	})
+	basic := func(s string) string { return "Basic " + base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	ctx := serve(handlers, "Authorization", basic("henry:s3cret"))
+	p, _ := ctx.UserValue("user").(*Principal)
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "henry", p.Subject)
+		assert.Equal(t, "Basic", p.Scheme)
+	}
+	for _, v := range []string{"", basic("henry:wrong"), basic("nobody:s3cret"), "Basic !!!", "Bearer x"} {
+		ctx = serve(handlers, "Authorization", v)
+		assert.Nil(t, ctx.UserValue("user"), v)
+		assert.Equal(t, `Basic realm="admin", charset="UTF-8"`, string(ctx.Response.Header.Peek("WWW-Authenticate")), v)
+	}
+}
+
+func TestAPIKeyCtl(t *testing.T) {
+	mw := NewAPIKeyCtl("", func(key string) (*Principal, error) {
+		switch key {
+		case "k1":
+			return &Principal{Subject: "henry", Roles: []string{"reader"}}, nil
+		case "down":
+			return nil, errors.New("database is down")
+		}
+		return nil, nil
+	})
+	handlers := rester.MustMakeHandlers(func() rester.Controller {
+		return &APIKeyUserCtl{APIKeyCtl: *mw}
+	})
+
+	ctx := serve(handlers, "X-API-Key", "k1")
+	p, _ := ctx.UserValue("user").(*Principal)
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "APIKey", p.Scheme)
+		assert.True(t, p.Has("reader"))
+		assert.False(t, p.Has("admin"))
+	}
+	for _, v := range []string{"", "k2", "down"} {
+		ctx = serve(handlers, "X-API-Key", v)
+		assert.Nil(t, ctx.UserValue("user"), v)
+	}
+}
+
+func TestJWTCtl(t *testing.T) {
+	secret := testSecret
+	keys := NewKeySet()
+	assert.NoError(t, keys.AddHMAC("", secret))
+	mw := NewJWTCtl(NewJWTVerifier(keys, &JWTConfig{Realm: "api"}))
+	handlers := rester.MustMakeHandlers(func() rester.Controller {
+		return &JWTUserCtl{JWTCtl: *mw}
+	})
+
+	ctx := serve(handlers, "Authorization", "bearer "+signToken(t, "HS256", "", secret, map[string]interface{}{"sub": "henry"}))
+	p, _ := ctx.UserValue("user").(*Principal)
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "henry", p.Subject)
+	}
+
+	ctx = serve(handlers, "Authorization", "")
+	assert.Nil(t, ctx.UserValue("user"))
+	assert.Equal(t, `Bearer realm="api"`, string(ctx.Response.Header.Peek("WWW-Authenticate")))
+
+	ctx = serve(handlers, "Authorization", "Bearer "+signToken(t, "HS256", "", []byte("other"), map[string]interface{}{"sub": "henry"}))
+	assert.Nil(t, ctx.UserValue("user"))
+	assert.Equal(t, `Bearer realm="api", error="invalid_token", error_description="invalid token signature"`,
+		string(ctx.Response.Header.Peek("WWW-Authenticate")))
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/henrylee2cn/rester"
+)
+
+// BasicValidator validates the user name and password of HTTP Basic authentication,
+// returns nil principal if they are invalid, or an error if the validation cannot be done.
+type BasicValidator func(username, password string) (*Principal, error)
+
+// BasicCtl the middleware controller of HTTP Basic authentication, e.g.
+//   admin := engine.Group("/admin", auth.NewBasicCtl("admin", auth.BasicAccounts(accounts)))
+// NOTE:
+//  It should only be used over HTTPS.
+type BasicCtl struct {
+	rester.BaseCtl
+	challenge string
+	validate  BasicValidator
+}
+
+// NewBasicCtl creates the middleware controller of HTTP Basic authentication.
+func NewBasicCtl(realm string, validate BasicValidator) *BasicCtl {
+	return &BasicCtl{
+		challenge: "Basic realm=" + strconv.Quote(realm) + `, charset="UTF-8"`,
+		validate:  validate,
+	}
+}
+
+// Any authenticates the request, and aborts with 401 Unauthorized if it fails.
+func (ctl *BasicCtl) Any() error {
+	credentials, ok := authorization(ctl.RequestCtx, "Basic")
+	if !ok {
+		unauthorized(&ctl.BaseCtl, ctl.challenge, "missing basic credentials")
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		unauthorized(&ctl.BaseCtl, ctl.challenge, "malformed basic credentials")
+		return nil
+	}
+	username, password := string(b), ""
+	if i := strings.IndexByte(username, ':'); i >= 0 {
+		username, password = username[:i], username[i+1:]
+	}
+	p, err := ctl.validate(username, password)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		unauthorized(&ctl.BaseCtl, ctl.challenge, "invalid user name or password")
+		return nil
+	}
+	if p.Subject == "" {
+		p.Subject = username
+	}
+	p.Scheme = "Basic"
+	SetPrincipal(ctl.RequestCtx, p)
+	return nil
+}
+
+// BasicAccounts returns the validator of the fixed accounts {username:password},
+// the passwords are compared in constant time.
+func BasicAccounts(accounts map[string]string) BasicValidator {
+	return func(username, password string) (*Principal, error) {
+		expected, ok := accounts[username]
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(password)) != 1 || !ok {
+			return nil, nil
+		}
+		return &Principal{Subject: username}, nil
+	}
+}
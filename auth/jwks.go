@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sync"
+)
+
+// KeySet the keys verifying the signatures of JWT,
+// the HMAC secrets, the RSA and ECDSA public keys.
+// NOTE:
+//  It is safe for concurrent use, the keys can be changed while the server is running.
+type KeySet struct {
+	lock sync.RWMutex
+	keys []jwk
+}
+
+type jwk struct {
+	id  string
+	alg string
+	// []byte, *rsa.PublicKey or *ecdsa.PublicKey
+	key interface{}
+}
+
+// NewKeySet creates an empty key set.
+func NewKeySet() *KeySet {
+	return new(KeySet)
+}
+
+// minHMACSize the minimum size of the HMAC secret, which is the size of SHA-256
+const minHMACSize = 32
+
+var errShortHMAC = fmt.Errorf("the HMAC secret must have at least %d bytes", minHMACSize)
+
+// AddHMAC adds the secret of the HS256, HS384 or HS512 algorithm,
+// kid is the 'kid' header of the tokens signed by it, which can be empty.
+// NOTE:
+//  The secret must have at least 32 bytes, and it is only used by HS384 and HS512 if it has at least 48 and 64 bytes.
+func (s *KeySet) AddHMAC(kid string, secret []byte) error {
+	if len(secret) < minHMACSize {
+		return errShortHMAC
+	}
+	s.add(jwk{id: kid, key: append([]byte(nil), secret...)})
+	return nil
+}
+
+// AddPublicKey adds the public key of the RS* or ES* algorithm,
+// which must be *rsa.PublicKey or *ecdsa.PublicKey.
+func (s *KeySet) AddPublicKey(kid string, key crypto.PublicKey) error {
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	s.add(jwk{id: kid, key: key})
+	return nil
+}
+
+func (s *KeySet) add(k jwk) {
+	s.lock.Lock()
+	s.keys = append(s.keys, k)
+	s.lock.Unlock()
+}
+
+// Len returns the number of the keys.
+func (s *KeySet) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.keys)
+}
+
+// LoadJWKSFile loads the key set from the local JWKS file (RFC 7517).
+func LoadJWKSFile(filename string) (*KeySet, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses the key set from the JWKS document (RFC 7517),
+// the keys which are not used for signatures or whose types are not supported are ignored.
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			K   string `json:"k"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	s := NewKeySet()
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		var err error
+		switch k.Kty {
+		case "oct":
+			var secret []byte
+			if secret, err = jwtEncoding.DecodeString(k.K); err == nil && len(secret) < minHMACSize {
+				err = errShortHMAC
+			}
+			key = secret
+		case "RSA":
+			key, err = parseRSAKey(k.N, k.E)
+		case "EC":
+			key, err = parseECKey(k.Crv, k.X, k.Y)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %s", k.Kid, err)
+		}
+		s.keys = append(s.keys, jwk{id: k.Kid, alg: k.Alg, key: key})
+	}
+	return s, nil
+}
+
+func parseRSAKey(n, e string) (*rsa.PublicKey, error) {
+	nb, err := jwtEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := jwtEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	if len(nb) == 0 || len(eb) == 0 || len(eb) > 4 {
+		return nil, errors.New("invalid RSA modulus or exponent")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nb),
+		E: int(new(big.Int).SetBytes(eb).Int64()),
+	}, nil
+}
+
+func parseECKey(crv, x, y string) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", crv)
+	}
+	xb, err := jwtEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yb, err := jwtEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(xb), Y: new(big.Int).SetBytes(yb)}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("the point is not on the curve")
+	}
+	return key, nil
+}
+
+// verify reports whether the signature is verified by any key matching the kid and algorithm.
+func (s *KeySet) verify(kid, algName string, alg algorithm, signed, sig []byte) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, k := range s.keys {
+		if kid != "" && k.id != kid {
+			continue
+		}
+		if k.alg != "" && k.alg != algName {
+			continue
+		}
+		if alg.verify(k.key, signed, sig) {
+			return true
+		}
+	}
+	return false
+}
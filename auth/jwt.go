@@ -0,0 +1,293 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrylee2cn/goutil"
+
+	"github.com/henrylee2cn/rester"
+)
+
+// JWTConfig the claims checked by JWTVerifier
+type JWTConfig struct {
+	// Realm the realm of the WWW-Authenticate header
+	Realm string
+	// Audience if not empty, the 'aud' claim must contain it
+	Audience string
+	// Issuer if not empty, the 'iss' claim must be equal to it
+	Issuer string
+	// Leeway the allowed clock skew when checking the 'exp' and 'nbf' claims
+	Leeway time.Duration
+	// RequireExp if set to true, the token without the 'exp' claim is rejected,
+	// otherwise it never expires
+	RequireExp bool
+	// RolesClaim the claim of the roles, use 'roles' by default when empty,
+	// whose value is an array of strings, or a string separated by spaces such as 'scope'
+	RolesClaim string
+}
+
+// JWTVerifier verifies the JWT (RFC 7519) signed by HS256, HS384, HS512,
+// RS256, RS384, RS512, ES256, ES384 or ES512.
+type JWTVerifier struct {
+	keys   *KeySet
+	config JWTConfig
+}
+
+// NewJWTVerifier creates the verifier of the tokens signed by the keys,
+// the default config is used if config is nil.
+func NewJWTVerifier(keys *KeySet, config *JWTConfig) *JWTVerifier {
+	if config == nil {
+		config = new(JWTConfig)
+	}
+	v := &JWTVerifier{keys: keys, config: *config}
+	goutil.InitAndGetString(&v.config.RolesClaim, "roles")
+	return v
+}
+
+var (
+	jwtEncoding = base64.RawURLEncoding
+
+	errMalformedToken   = errors.New("malformed token")
+	errInvalidSignature = errors.New("invalid token signature")
+	errTokenExpired     = errors.New("token is expired")
+	errTokenNotValidYet = errors.New("token is not valid yet")
+	errMissingExp       = errors.New("token has no expiration time")
+	errInvalidAudience  = errors.New("invalid token audience")
+	errInvalidIssuer    = errors.New("invalid token issuer")
+)
+
+// now returns the current time, it is replaced in tests.
+var now = time.Now
+
+// Verify verifies the signature and the claims of the token, and returns its principal.
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformedToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if decodeSegment(parts[0], &header) != nil {
+		return nil, errMalformedToken
+	}
+	alg, ok := algorithms[header.Alg]
+	if !ok {
+		return nil, errors.New("unsupported token algorithm: " + header.Alg)
+	}
+	sig, err := jwtEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	signed := token[:len(parts[0])+1+len(parts[1])]
+	if !v.keys.verify(header.Kid, header.Alg, alg, []byte(signed), sig) {
+		return nil, errInvalidSignature
+	}
+	var claims map[string]interface{}
+	if decodeSegment(parts[1], &claims) != nil || claims == nil {
+		return nil, errMalformedToken
+	}
+	if err = v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	sub, _ := claims["sub"].(string)
+	return &Principal{
+		Subject: sub,
+		Scheme:  "Bearer",
+		Roles:   claimStrings(claims[v.config.RolesClaim]),
+		Claims:  claims,
+	}, nil
+}
+
+func (v *JWTVerifier) checkClaims(claims map[string]interface{}) error {
+	t := now()
+	exp, ok, err := timeClaim(claims, "exp")
+	if err != nil {
+		return err
+	}
+	if ok && !t.Before(exp.Add(v.config.Leeway)) {
+		return errTokenExpired
+	}
+	if !ok && v.config.RequireExp {
+		return errMissingExp
+	}
+	nbf, ok, err := timeClaim(claims, "nbf")
+	if err != nil {
+		return err
+	}
+	if ok && t.Add(v.config.Leeway).Before(nbf) {
+		return errTokenNotValidYet
+	}
+	if v.config.Audience != "" {
+		var found bool
+		for _, aud := range audiences(claims["aud"]) {
+			if aud == v.config.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errInvalidAudience
+		}
+	}
+	if v.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+			return errInvalidIssuer
+		}
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := jwtEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// timeClaim returns the NumericDate claim, ok is false if it is absent.
+func timeClaim(claims map[string]interface{}, name string) (t time.Time, ok bool, err error) {
+	v, ok := claims[name]
+	if !ok {
+		return
+	}
+	n, _ := v.(json.Number)
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return t, false, errors.New("malformed token claim " + name)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)), true, nil
+}
+
+// audiences returns the 'aud' claim, which is a single audience if it is a string (RFC 7519).
+func audiences(v interface{}) []string {
+	if s, ok := v.(string); ok {
+		return []string{s}
+	}
+	return claimStrings(v)
+}
+
+// claimStrings returns the claim as strings,
+// which is an array of strings, or a string separated by spaces.
+func claimStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		a := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				a = append(a, s)
+			}
+		}
+		return a
+	}
+	return nil
+}
+
+type algorithm struct {
+	hash crypto.Hash
+	// 'H' for HMAC, 'R' for RSA PKCS #1 v1.5, 'E' for ECDSA
+	kind  byte
+	curve elliptic.Curve
+}
+
+var algorithms = map[string]algorithm{
+	"HS256": {hash: crypto.SHA256, kind: 'H'},
+	"HS384": {hash: crypto.SHA384, kind: 'H'},
+	"HS512": {hash: crypto.SHA512, kind: 'H'},
+	"RS256": {hash: crypto.SHA256, kind: 'R'},
+	"RS384": {hash: crypto.SHA384, kind: 'R'},
+	"RS512": {hash: crypto.SHA512, kind: 'R'},
+	"ES256": {hash: crypto.SHA256, kind: 'E', curve: elliptic.P256()},
+	"ES384": {hash: crypto.SHA384, kind: 'E', curve: elliptic.P384()},
+	"ES512": {hash: crypto.SHA512, kind: 'E', curve: elliptic.P521()},
+}
+
+// verify reports whether the signature is verified by the key,
+// the key is skipped if its type does not match the algorithm.
+func (a algorithm) verify(key interface{}, signed, sig []byte) bool {
+	switch k := key.(type) {
+	case []byte:
+		if a.kind != 'H' || len(k) < a.hash.Size() {
+			return false
+		}
+		mac := hmac.New(a.hash.New, k)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), sig)
+	case *rsa.PublicKey:
+		if a.kind != 'R' {
+			return false
+		}
+		h := a.hash.New()
+		h.Write(signed)
+		return rsa.VerifyPKCS1v15(k, a.hash, h.Sum(nil), sig) == nil
+	case *ecdsa.PublicKey:
+		if a.kind != 'E' || k.Curve.Params().Name != a.curve.Params().Name {
+			return false
+		}
+		size := (a.curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		h := a.hash.New()
+		h.Write(signed)
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, h.Sum(nil), r, s)
+	}
+	return false
+}
+
+// JWTCtl the middleware controller of the Bearer JWT in the Authorization header, e.g.
+//   keys, err := auth.LoadJWKSFile("jwks.json")
+//   api := engine.Group("/api", auth.NewJWTCtl(auth.NewJWTVerifier(keys, &auth.JWTConfig{Audience: "api"})))
+// NOTE:
+//  The roles of the principal are read from JWTConfig.RolesClaim.
+type JWTCtl struct {
+	rester.BaseCtl
+	verifier *JWTVerifier
+}
+
+// NewJWTCtl creates the middleware controller of the Bearer JWT.
+func NewJWTCtl(verifier *JWTVerifier) *JWTCtl {
+	return &JWTCtl{verifier: verifier}
+}
+
+// Any authenticates the request, and aborts with 401 Unauthorized if it fails.
+func (ctl *JWTCtl) Any() {
+	challenge := "Bearer realm=" + strconv.Quote(ctl.verifier.config.Realm)
+	token, ok := authorization(ctl.RequestCtx, "Bearer")
+	if !ok {
+		unauthorized(&ctl.BaseCtl, challenge, "missing bearer token")
+		return
+	}
+	p, err := ctl.verifier.Verify(token)
+	if err != nil {
+		// RFC 6750
+		challenge += `, error="invalid_token", error_description=` + strconv.Quote(err.Error())
+		unauthorized(&ctl.BaseCtl, challenge, err.Error())
+		return
+	}
+	SetPrincipal(ctl.RequestCtx, p)
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSecret the HMAC secret long enough for HS512
+var testSecret = []byte(strings.Repeat("0123456789abcdef", 4))
+
+// signToken signs the token for the tests.
+func signToken(t *testing.T, alg, kid string, key interface{}, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := jwtEncoding.EncodeToString(header) + "." + jwtEncoding.EncodeToString(payload)
+	a := algorithms[alg]
+	h := a.hash.New()
+	h.Write([]byte(signed))
+	var sig []byte
+	var err error
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(a.hash.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, a.hash, h.Sum(nil))
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, h.Sum(nil))
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig = make([]byte, 2*size)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(sig[size-len(rb):size], rb)
+		copy(sig[2*size-len(sb):], sb)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + jwtEncoding.EncodeToString(sig)
+}
+
+func TestJWTVerifier(t *testing.T) {
+	clock := time.Unix(1600000000, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	secret := testSecret
+	keys := NewKeySet()
+	assert.NoError(t, keys.AddHMAC("hs", secret))
+	assert.NoError(t, keys.AddPublicKey("rs", rsaKey.Public()))
+	assert.NoError(t, keys.AddPublicKey("es", ecKey.Public()))
+	assert.Error(t, keys.AddPublicKey("x", "pem"))
+	v := NewJWTVerifier(keys, &JWTConfig{Audience: "api", Issuer: "me", Leeway: time.Minute})
+
+	claims := func(kv ...interface{}) map[string]interface{} {
+		m := map[string]interface{}{"sub": "henry", "aud": []string{"web", "api"}, "iss": "me", "exp": clock.Unix() + 60, "roles": []string{"admin"}}
+		for i := 0; i < len(kv); i += 2 {
+			if kv[i+1] == nil {
+				delete(m, kv[i].(string))
+			} else {
+				m[kv[i].(string)] = kv[i+1]
+			}
+		}
+		return m
+	}
+	var cases = []struct {
+		alg    string
+		kid    string
+		key    interface{}
+		claims map[string]interface{}
+		err    string
+	}{
+		{"HS256", "hs", secret, claims(), ""},
+		{"HS512", "", secret, claims("aud", "api"), ""},
+		{"RS256", "rs", rsaKey, claims(), ""},
+		{"RS384", "", rsaKey, claims("exp", nil), ""},
+		{"ES256", "es", ecKey, claims(), ""},
+		{"HS256", "hs", []byte("other"), claims(), "invalid token signature"},
+		{"HS256", "rs", secret, claims(), "invalid token signature"},
+		{"HS256", "hs", secret, claims("exp", clock.Unix()-60), "token is expired"},
+		{"HS256", "hs", secret, claims("exp", clock.Unix()-30), ""},
+		{"HS256", "hs", secret, claims("nbf", clock.Unix()+120), "token is not valid yet"},
+		{"HS256", "hs", secret, claims("exp", "tomorrow"), "malformed token claim exp"},
+		{"HS256", "hs", secret, claims("aud", "web"), "invalid token audience"},
+		{"HS256", "hs", secret, claims("aud", "web api"), "invalid token audience"},
+		{"HS256", "hs", secret, claims("iss", nil), "invalid token issuer"},
+	}
+	for i, c := range cases {
+		p, err := v.Verify(signToken(t, c.alg, c.kid, c.key, c.claims))
+		if c.err != "" {
+			assert.EqualError(t, err, c.err, i)
+			continue
+		}
+		if assert.NoError(t, err, i) {
+			assert.Equal(t, "henry", p.Subject, i)
+			assert.Equal(t, "Bearer", p.Scheme, i)
+			assert.True(t, p.Has("admin"), i)
+		}
+	}
+
+	// the public key cannot be used as the HMAC secret
+	_, err = v.Verify(signToken(t, "HS256", "", []byte("rs"), claims()))
+	assert.EqualError(t, err, "invalid token signature")
+	_, err = v.Verify(`eyJhbGciOiJub25lIn0.eyJzdWIiOiJoZW5yeSJ9.`)
+	assert.EqualError(t, err, "unsupported token algorithm: none")
+	_, err = v.Verify("a.b")
+	assert.EqualError(t, err, "malformed token")
+
+	v = NewJWTVerifier(keys, &JWTConfig{RequireExp: true})
+	_, err = v.Verify(signToken(t, "HS256", "hs", secret, claims("exp", nil)))
+	assert.EqualError(t, err, "token has no expiration time")
+	_, err = v.Verify(signToken(t, "HS256", "hs", secret, claims()))
+	assert.NoError(t, err)
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	b64 := func(i *big.Int) string { return jwtEncoding.EncodeToString(i.Bytes()) }
+	doc := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rs","alg":"RS256","use":"sig","n":%q,"e":%q},
+		{"kty":"EC","kid":"es","crv":"P-384","x":%q,"y":%q},
+		{"kty":"oct","kid":"hs","k":%q},
+		{"kty":"RSA","kid":"enc","use":"enc","n":"AQAB","e":"AQAB"},
+		{"kty":"OKP","kid":"ed","crv":"Ed25519","x":"AQAB"}
+	]}`, b64(rsaKey.N), b64(big.NewInt(int64(rsaKey.E))), b64(ecKey.X), b64(ecKey.Y), jwtEncoding.EncodeToString(testSecret))
+	keys, err := ParseJWKS([]byte(doc))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, keys.Len())
+	v := NewJWTVerifier(keys, nil)
+	claims := map[string]interface{}{"sub": "henry", "scope": "read write"}
+	for _, c := range []struct {
+		alg string
+		key interface{}
+	}{{"RS256", rsaKey}, {"ES384", ecKey}, {"HS384", testSecret}} {
+		_, err = v.Verify(signToken(t, c.alg, "", c.key, claims))
+		assert.NoError(t, err, c.alg)
+	}
+	// the algorithm of the JWK is enforced
+	_, err = v.Verify(signToken(t, "RS512", "rs", rsaKey, claims))
+	assert.EqualError(t, err, "invalid token signature")
+
+	p, err := NewJWTVerifier(keys, &JWTConfig{RolesClaim: "scope"}).Verify(signToken(t, "HS256", "hs", testSecret, claims))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"read", "write"}, p.Roles)
+	}
+
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"oct","kid":"empty"}]}`))
+	assert.EqualError(t, err, `invalid JWK "empty": the HMAC secret must have at least 32 bytes`)
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"bad","crv":"P-256","x":"AQAB","y":"AQAB"}]}`))
+	assert.EqualError(t, err, `invalid JWK "bad": the point is not on the curve`)
+}
+
+func TestWeakHMAC(t *testing.T) {
+	claims := map[string]interface{}{"sub": "attacker", "roles": []string{"admin"}}
+	keys := NewKeySet()
+	assert.EqualError(t, keys.AddHMAC("", nil), "the HMAC secret must have at least 32 bytes")
+	assert.EqualError(t, keys.AddHMAC("", []byte("short")), "the HMAC secret must have at least 32 bytes")
+	assert.Equal(t, 0, keys.Len())
+	_, err := NewJWTVerifier(keys, nil).Verify(signToken(t, "HS256", "", []byte{}, claims))
+	assert.EqualError(t, err, "invalid token signature")
+
+	// the secret shorter than the hash is not used by the algorithm
+	assert.NoError(t, keys.AddHMAC("", testSecret[:32]))
+	v := NewJWTVerifier(keys, nil)
+	_, err = v.Verify(signToken(t, "HS256", "", testSecret[:32], claims))
+	assert.NoError(t, err)
+	_, err = v.Verify(signToken(t, "HS512", "", testSecret[:32], claims))
+	assert.EqualError(t, err, "invalid token signature")
+}
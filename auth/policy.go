@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/henrylee2cn/rester"
+)
+
+// Policy the RBAC and ABAC policy, which grants the permissions to the principal by its roles and claims, e.g.
+//   {
+//     "roles": {
+//       "viewer": {"permissions": ["orders:read"]},
+//       "editor": {"permissions": ["orders:write"], "inherits": ["viewer"]},
+//       "admin": {"permissions": ["*"]}
+//     },
+//     "rules": [
+//       {"permissions": ["reports:read"], "claims": {"department": ["finance", "audit"]}}
+//     ]
+//   }
+// NOTE:
+//  The pattern '*' matches all the permissions, and 'orders:*' matches the permissions beginning with 'orders:';
+//  The rule grants its permissions if the principal has one of the listed values of every claim,
+//  and it must have at least one claim.
+type Policy struct {
+	// roles {role:permission patterns}, including the inherited ones
+	roles map[string][]string
+	rules []policyRule
+}
+
+type policyRule struct {
+	permissions []string
+	claims      map[string][]string
+}
+
+var _ rester.Policy = new(Policy)
+
+// LoadPolicyFile loads the policy from the JSON file.
+func LoadPolicyFile(filename string) (*Policy, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses the policy from the JSON document.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var doc struct {
+		Roles map[string]struct {
+			Permissions []string `json:"permissions"`
+			Inherits    []string `json:"inherits"`
+		} `json:"roles"`
+		Rules []struct {
+			Permissions []string            `json:"permissions"`
+			Claims      map[string][]string `json:"claims"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	p := &Policy{roles: make(map[string][]string, len(doc.Roles))}
+	for name, role := range doc.Roles {
+		for _, parent := range role.Inherits {
+			if _, ok := doc.Roles[parent]; !ok {
+				return nil, fmt.Errorf("the role %q inherits the unknown role %q", name, parent)
+			}
+		}
+		// flatten the inherited permissions, the cycles are ignored
+		var permissions []string
+		visited := map[string]bool{}
+		var flatten func(string)
+		flatten = func(role string) {
+			if visited[role] {
+				return
+			}
+			visited[role] = true
+			permissions = append(permissions, doc.Roles[role].Permissions...)
+			for _, parent := range doc.Roles[role].Inherits {
+				flatten(parent)
+			}
+		}
+		flatten(name)
+		p.roles[name] = permissions
+	}
+	for i, r := range doc.Rules {
+		// the rule without claims would grant its permissions to every principal
+		if len(r.Claims) == 0 {
+			return nil, fmt.Errorf("the rule %d has no claims", i)
+		}
+		p.rules = append(p.rules, policyRule{permissions: r.Permissions, claims: r.Claims})
+	}
+	return p, nil
+}
+
+// Allowed implements rester.Policy, decides by the principal of the request.
+func (p *Policy) Allowed(ctx *rester.RequestCtx, permissions []string) bool {
+	return p.Grants(GetPrincipal(ctx), permissions)
+}
+
+// Grants returns true if the principal is granted all the permissions,
+// it is always false for the nil principal.
+func (p *Policy) Grants(principal *Principal, permissions []string) bool {
+	if principal == nil {
+		return false
+	}
+	var patterns []string
+	for _, role := range principal.Roles {
+		patterns = append(patterns, p.roles[role]...)
+	}
+	for _, r := range p.rules {
+		if r.match(principal) {
+			patterns = append(patterns, r.permissions...)
+		}
+	}
+	for _, permission := range permissions {
+		if !matchAny(patterns, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *policyRule) match(principal *Principal) bool {
+	for claim, allowed := range r.claims {
+		var ok bool
+		for _, v := range claimValues(principal.Claims[claim]) {
+			for _, a := range allowed {
+				if v == a {
+					ok = true
+				}
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// claimValues returns the claim as strings for the comparison.
+func claimValues(v interface{}) []string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		a := make([]string, 0, len(v))
+		for _, e := range v {
+			a = append(a, fmt.Sprint(e))
+		}
+		return a
+	}
+	return []string{fmt.Sprint(v)}
+}
+
+func matchAny(patterns []string, permission string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == permission ||
+			strings.HasSuffix(pattern, "*") && strings.HasPrefix(permission, pattern[:len(pattern)-1]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestPolicy(t *testing.T) {
+	p, err := ParsePolicy([]byte(`{
+		"roles": {
+			"viewer": {"permissions": ["orders:read"]},
+			"editor": {"permissions": ["orders:write"], "inherits": ["viewer", "auditor"]},
+			"auditor": {"permissions": ["reports:*"], "inherits": ["editor"]},
+			"admin": {"permissions": ["*"]}
+		},
+		"rules": [
+			{"permissions": ["billing:read"], "claims": {"department": ["finance"], "level": ["2", "3"]}}
+		]
+	}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var cases = []struct {
+		principal   *Principal
+		permissions []string
+		granted     bool
+	}{
+		{nil, nil, false},
+		{&Principal{Roles: []string{"viewer"}}, []string{"orders:read"}, true},
+		{&Principal{Roles: []string{"viewer"}}, []string{"orders:read", "orders:write"}, false},
+		{&Principal{Roles: []string{"editor"}}, []string{"orders:read", "orders:write", "reports:daily"}, true},
+		{&Principal{Roles: []string{"auditor"}}, []string{"orders:write"}, true},
+		{&Principal{Roles: []string{"admin"}}, []string{"users:delete"}, true},
+		{&Principal{Roles: []string{"unknown"}}, []string{"orders:read"}, false},
+		{&Principal{Claims: map[string]interface{}{"department": "finance", "level": 2}}, []string{"billing:read"}, true},
+		{&Principal{Claims: map[string]interface{}{"department": []interface{}{"it", "finance"}, "level": "3"}}, []string{"billing:read"}, true},
+		{&Principal{Claims: map[string]interface{}{"department": "finance", "level": 1}}, []string{"billing:read"}, false},
+		{&Principal{Claims: map[string]interface{}{"department": "finance"}}, []string{"billing:read"}, false},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.granted, p.Grants(c.principal, c.permissions), i)
+	}
+
+	ctx := new(fasthttp.RequestCtx)
+	assert.False(t, p.Allowed(ctx, []string{"orders:read"}))
+	SetPrincipal(ctx, &Principal{Roles: []string{"viewer"}})
+	assert.True(t, p.Allowed(ctx, []string{"orders:read"}))
+
+	_, err = ParsePolicy([]byte(`{"roles": {"editor": {"inherits": ["viewer"]}}}`))
+	assert.EqualError(t, err, `the role "editor" inherits the unknown role "viewer"`)
+
+	_, err = ParsePolicy([]byte(`{"rules": [{"permissions": ["*"], "claims": {}}]}`))
+	assert.EqualError(t, err, `the rule 0 has no claims`)
+	_, err = ParsePolicy([]byte(`{"rules": [{"permissions": ["*"]}]}`))
+	assert.EqualError(t, err, `the rule 0 has no claims`)
+}
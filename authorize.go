@@ -0,0 +1,148 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/henrylee2cn/ameda"
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// Authorizer optional interface of Controller,
+	// provides the permissions required by its methods, e.g.
+	//   func (*OrderCtl) Authorize() map[string][]string {
+	//       return map[string][]string{"GET": {"orders:read"}, "POST": {"orders:write"}}
+	//   }
+	// NOTE:
+	//  The key 'Any' means all HTTP methods;
+	//  It is called once when the controller is registered, so it must not use the request context.
+	Authorizer interface {
+		Authorize() map[string][]string
+	}
+	// Policy decides whether the request is granted all the permissions required by the controller,
+	// it is consulted after all the middlewares, just before the controller's own method.
+	Policy interface {
+		Allowed(ctx *RequestCtx, permissions []string) bool
+	}
+	// PolicyFunc the function implementing Policy
+	PolicyFunc func(ctx *RequestCtx, permissions []string) bool
+	// RoutePermission the permissions required by the route
+	RoutePermission struct {
+		Method      string
+		Path        string
+		Controller  string
+		Permissions []string
+	}
+)
+
+// authorizeTag the struct tag of the permissions required by all the methods of the controller,
+// which is set on the embedded fields, such as the authentication middleware, e.g.
+//   type OrderCtl struct {
+//       auth.JWTCtl `authorize:"orders:read,orders:export"`
+//   }
+const authorizeTag = "authorize"
+
+// Allowed implements Policy.
+func (f PolicyFunc) Allowed(ctx *RequestCtx, permissions []string) bool {
+	return f(ctx, permissions)
+}
+
+// SetPolicy sets the policy authorizing the controllers registered later,
+// including the controllers in the sub-groups.
+// NOTE:
+//  Registering the controller that requires permissions panics if no policy is set.
+func (r *Router) SetPolicy(p Policy) {
+	r.policy = p
+}
+
+// policyOf returns the policy of the nearest router.
+func (r *Router) policyOf() Policy {
+	for ; r != nil; r = r.parent {
+		if r.policy != nil {
+			return r.policy
+		}
+	}
+	return nil
+}
+
+// RoutePermissions returns the permissions required by the registered routes in the registration order,
+// the permissions of the public routes are empty.
+func (r *Router) RoutePermissions() []RoutePermission {
+	routes := r.routeList()
+	list := make([]RoutePermission, len(routes))
+	for i, rt := range routes {
+		list[i] = RoutePermission{
+			Method:      rt.httpMethod,
+			Path:        rt.path,
+			Controller:  rt.controller.PkgPath() + "." + rt.controller.Name(),
+			Permissions: rt.permissions,
+		}
+	}
+	return list
+}
+
+// permissionsOf returns the function returning the permissions required by the HTTP method of the controller,
+// which are declared by the authorize tags of the embedded fields and the Authorize method.
+// NOTE:
+//  The Authorize method is called only once here.
+func permissionsOf(c Controller) func(httpMethod string) []string {
+	var tagged []string
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.Anonymous {
+				continue
+			}
+			if tag, ok := field.Tag.Lookup(authorizeTag); ok {
+				tagged = append(tagged, strings.Split(tag, ",")...)
+			}
+			if field.Type.Kind() == reflect.Struct {
+				walk(field.Type)
+			}
+		}
+	}
+	walk(ameda.DereferenceType(reflect.TypeOf(c)))
+	var declared map[string][]string
+	if a, ok := c.(Authorizer); ok {
+		declared = a.Authorize()
+	}
+	return func(httpMethod string) []string {
+		var permissions []string
+		for _, a := range [][]string{tagged, declared[anyMethod], declared[httpMethod]} {
+			for _, p := range a {
+				if p = strings.TrimSpace(p); p != "" && !ameda.StringsIncludes(permissions, p) {
+					permissions = append(permissions, p)
+				}
+			}
+		}
+		return permissions
+	}
+}
+
+// Guard checks the permissions required by the controller before its own method is called,
+// and calls Forbidden if the policy denies.
+func (a argsRequestCtx) Guard(recv reflect.Value) error {
+	if len(a.permissions) == 0 {
+		return nil
+	}
+	if !a.policy.Allowed(a.RequestCtx, a.permissions) {
+		recv.Interface().(Controller).Forbidden(fasthttp.StatusForbidden, "permission denied")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package rester
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/henrylee2cn/ameda"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type RolesMwCtl struct {
+	BaseCtl
+}
+
+func (ctl *RolesMwCtl) Any() {
+	ctl.SetUserValue("roles", strings.Split(string(ctl.Request.Header.Peek("X-Roles")), ","))
+}
+
+type OrderCtl struct {
+	RolesMwCtl `authorize:"orders:read"`
+}
+
+var authorizeCalls int
+
+func (*OrderCtl) Authorize() map[string][]string {
+	authorizeCalls++
+	return map[string][]string{"POST": {"orders:write"}, "Any": {"orders:read"}}
+}
+
+func (ctl *OrderCtl) GET() {
+	ctl.OK(H{"id": 1})
+}
+
+func (ctl *OrderCtl) POST() {
+	ctl.Created("/orders/2", H{"id": 2})
+}
+
+type PublicCtl struct {
+	BaseCtl
+}
+
+func (ctl *PublicCtl) GET() {
+	ctl.OK(H{"public": true})
+}
+
+func TestAuthorize(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	r := New()
+	var checked [][]string
+	r.SetPolicy(PolicyFunc(func(ctx *RequestCtx, permissions []string) bool {
+		checked = append(checked, permissions)
+		roles, _ := ctx.UserValue("roles").([]string)
+		for _, p := range permissions {
+			if !ameda.StringsIncludes(roles, p) {
+				return false
+			}
+		}
+		return true
+	}))
+	authorizeCalls = 0
+	r.DefControl("/orders", new(OrderCtl))
+	assert.Equal(t, 1, authorizeCalls)
+	r.DefControl("/public", new(PublicCtl))
+
+	var cases = []struct {
+		method string
+		uri    string
+		roles  string
+		status int
+		body   string
+	}{
+		{"GET", "/orders", "orders:read", 200, `{"id":1}`},
+		{"GET", "/orders", "", 403, `{"code":403,"msg":"permission denied"}`},
+		{"POST", "/orders", "orders:read", 403, `{"code":403,"msg":"permission denied"}`},
+		{"POST", "/orders", "orders:read,orders:write", 201, `{"id":2}`},
+		{"GET", "/public", "", 200, `{"public":true}`},
+	}
+	for _, c := range cases {
+		ctx := new(fasthttp.RequestCtx)
+		ctx.Init(new(fasthttp.Request), nil, nil)
+		ctx.Request.Header.SetMethod(c.method)
+		ctx.Request.SetRequestURI(c.uri)
+		ctx.Request.Header.Set("X-Roles", c.roles)
+		r.router.Handler(ctx)
+		assert.Equal(t, c.status, ctx.Response.StatusCode(), c)
+		assert.Equal(t, c.body, string(ctx.Response.Body()), c)
+	}
+	assert.Equal(t, [][]string{{"orders:read"}, {"orders:read"}, {"orders:read", "orders:write"}, {"orders:read", "orders:write"}}, checked)
+
+	assert.Equal(t, []RoutePermission{
+		{Method: "GET", Path: "/orders", Controller: "github.com/henrylee2cn/rester.OrderCtl", Permissions: []string{"orders:read"}},
+		{Method: "POST", Path: "/orders", Controller: "github.com/henrylee2cn/rester.OrderCtl", Permissions: []string{"orders:read", "orders:write"}},
+		{Method: "GET", Path: "/public", Controller: "github.com/henrylee2cn/rester.PublicCtl"},
+	}, r.RoutePermissions())
+
+	_, err := NewHandlers(new(OrderCtl))
+	assert.EqualError(t, err, "*rester.OrderCtl requires permissions [orders:read] but no policy is set")
+}
@@ -19,8 +19,12 @@ import (
 type Binding struct {
 	vd             *validator.Validator
 	recvs          map[int32]*receiver
+	bodyCodecs     *bodyCodecs
 	lock           sync.RWMutex
 	bindErrFactory func(failField, msg string) error
+	vdErrFactory   func(failField, msg string) error
+	unmarshalers   *typeUnmarshalers
+	keyring        *Keyring
 	config         Config
 }
 
@@ -32,11 +36,16 @@ func New(config *Config) *Binding {
 		config = new(Config)
 	}
 	b := &Binding{
-		recvs:  make(map[int32]*receiver, 1024),
-		config: *config,
+		recvs:        make(map[int32]*receiver, 1024),
+		bodyCodecs:   newBodyCodecs(),
+		unmarshalers: newTypeUnmarshalers(globalTypeUnmarshalers),
+		config:       *config,
 	}
 	b.config.init()
 	b.vd = validator.New(b.config.Validator)
+	if err := b.RegisterBodyCodec([]string{"application/xml", "text/xml"}, tagXML, XMLCodec{}); err != nil {
+		panic(err)
+	}
 	return b.SetErrorFactory(nil, nil)
 }
 
@@ -47,14 +56,96 @@ func New(config *Config) *Binding {
 //  Suitable for these parameter types: query/header/cookie/form .
 func (b *Binding) SetLooseZeroMode(enable bool) *Binding {
 	b.config.LooseZeroMode = enable
+	b.resetReceivers()
+	return b
+}
+
+func (b *Binding) resetReceivers() {
+	b.lock.Lock()
 	for k := range b.recvs {
 		delete(b.recvs, k)
 	}
+	b.lock.Unlock()
+}
+
+// SetNotation sets the notation of the nested parameters in query and form,
+// such as 'filter[status]=open' for NotationBracket.
+// NOTE:
+//  The default is NotationNone.
+func (b *Binding) SetNotation(notation Notation) *Binding {
+	b.config.Notation = notation
+	b.resetReceivers()
+	return b
+}
+
+// SetStrict if set to true,
+// the unknown keys of query and form and the unknown members of JSON body are reported as binding errors.
+// NOTE:
+//  The default is false;
+//  The struct embedding Strict or NonStrict overrides it.
+func (b *Binding) SetStrict(enable bool) *Binding {
+	b.config.Strict = enable
+	b.resetReceivers()
+	return b
+}
+
+// SetCollectAllErrors if set to true,
+// binding and validating go on after a failure, and return Errors of all the failed fields.
+// NOTE:
+//  The default is false.
+func (b *Binding) SetCollectAllErrors(enable bool) *Binding {
+	b.config.CollectAllErrors = enable
+	return b
+}
+
+// SetKeyring sets the keyring to verify the 'signed' cookies and decrypt the 'encrypted' cookies,
+// e.g. `cookie:"session,signed"`.
+// NOTE:
+//  The tampered cookie is treated as missing, which fails the binding only if it is required;
+//  All the signed and encrypted cookies are treated as missing if the keyring is nil or empty.
+func (b *Binding) SetKeyring(k *Keyring) *Binding {
+	b.keyring = k
+	b.resetReceivers()
+	return b
+}
+
+// Keyring returns the keyring set by SetKeyring.
+func (b *Binding) Keyring() *Keyring {
+	return b.keyring
+}
+
+// RegTypeUnmarshal registers the unmarshalor function of type for this binding,
+// the function receives the context of the field, so that the type can be parsed differently by the position or options.
+// NOTE:
+//  It takes precedence over the function registered by the package-level RegTypeUnmarshal;
+//  It is safe to be called concurrently with binding;
+//  The function is tested with an empty FieldContext when registering.
+func (b *Binding) RegTypeUnmarshal(t reflect.Type, fn TypeUnmarshalFunc) error {
+	if err := b.unmarshalers.reg(t, fn); err != nil {
+		return err
+	}
+	b.resetReceivers()
+	return nil
+}
+
+// MustRegTypeUnmarshal registers the unmarshalor function of type for this binding.
+// NOTE:
+//  panic if exist error.
+func (b *Binding) MustRegTypeUnmarshal(t reflect.Type, fn TypeUnmarshalFunc) *Binding {
+	if err := b.RegTypeUnmarshal(t, fn); err != nil {
+		panic(err)
+	}
 	return b
 }
 
+const (
+	bindErrType     = "binding"
+	msgRequired     = "missing required parameter"
+	msgTypeMismatch = "parameter type does not match binding data"
+)
+
 var defaultValidatingErrFactory = newDefaultErrorFactory("validating")
-var defaultBindErrFactory = newDefaultErrorFactory("binding")
+var defaultBindErrFactory = newDefaultErrorFactory(bindErrType)
 
 // SetErrorFactory customizes the factory of validation error.
 // NOTE:
@@ -67,12 +158,16 @@ func (b *Binding) SetErrorFactory(bindErrFactory, validatingErrFactory func(fail
 		validatingErrFactory = defaultValidatingErrFactory
 	}
 	b.bindErrFactory = bindErrFactory
+	b.vdErrFactory = validatingErrFactory
 	b.vd.SetErrorFactory(validatingErrFactory)
 	return b
 }
 
 // BindAndValidate binds the request parameters and validates them if needed.
 func (b *Binding) BindAndValidate(recvPointer interface{}, req *fasthttp.RequestCtx) error {
+	if b.config.CollectAllErrors {
+		return b.bindAll(recvPointer, req, true)
+	}
 	v, hasVd, err := b.bind(recvPointer, req)
 	if err != nil {
 		return err
@@ -85,12 +180,18 @@ func (b *Binding) BindAndValidate(recvPointer interface{}, req *fasthttp.Request
 
 // Bind binds the request parameters.
 func (b *Binding) Bind(recvPointer interface{}, req *fasthttp.RequestCtx) error {
+	if b.config.CollectAllErrors {
+		return b.bindAll(recvPointer, req, false)
+	}
 	_, _, err := b.bind(recvPointer, req)
 	return err
 }
 
 // Validate validates whether the fields of value is valid.
 func (b *Binding) Validate(value interface{}) error {
+	if b.config.CollectAllErrors {
+		return b.validateAll(value, nil).err()
+	}
 	return b.vd.Validate(value)
 }
 
@@ -99,8 +200,10 @@ func (b *Binding) bind(pointer interface{}, req *fasthttp.RequestCtx) (elemValue
 	if err != nil {
 		return
 	}
-	if elemValue.Kind() == reflect.Struct {
-		hasVd, err = b.bindStruct(pointer, elemValue, req)
+	if binder, ok := pointer.(Binder); ok && elemValue.Kind() == reflect.Struct && b.binderCompatible() {
+		hasVd, err = b.bindBinder(binder, elemValue, req)
+	} else if elemValue.Kind() == reflect.Struct {
+		hasVd, err = b.bindStruct(pointer, elemValue, req, nil)
 	} else {
 		hasVd, err = b.bindNonstruct(pointer, elemValue, req)
 	}
@@ -108,7 +211,7 @@ func (b *Binding) bind(pointer interface{}, req *fasthttp.RequestCtx) (elemValue
 }
 
 func (b *Binding) bindNonstruct(pointer interface{}, _ reflect.Value, req *fasthttp.RequestCtx) (hasVd bool, err error) {
-	bodyCodec, bodyBytes, err := getBodyInfo(req)
+	bodyCodec, bodyBytes, err := getBodyInfo(req, b.bodyCodecs)
 	if err != nil {
 		return
 	}
@@ -127,6 +230,11 @@ func (b *Binding) bindNonstruct(pointer interface{}, _ reflect.Value, req *fasth
 		b, _ := jsonpkg.Marshal(v)
 		err = jsonpkg.Unmarshal(b, pointer)
 	default:
+		if bc := b.bodyCodecs.get(in(bodyCodec)); bc != nil {
+			hasVd = true
+			err = bc.codec.Unmarshal(bodyBytes, pointer)
+			break
+		}
 		// query and form
 		v := make(url.Values, 16)
 		req.QueryArgs().VisitAll(func(key, value []byte) {
@@ -147,7 +255,9 @@ func (b *Binding) bindNonstruct(pointer interface{}, _ reflect.Value, req *fasth
 	return
 }
 
-func (b *Binding) bindStruct(structPointer interface{}, structValue reflect.Value, req *fasthttp.RequestCtx) (hasVd bool, err error) {
+// bindStruct binds the struct fields,
+// if c is not nil, the binding goes on after a failure and the errors are collected into c.
+func (b *Binding) bindStruct(structPointer interface{}, structValue reflect.Value, req *fasthttp.RequestCtx, c *collector) (hasVd bool, err error) {
 	recv, err := b.getOrPrepareReceiver(structValue)
 	if err != nil {
 		return
@@ -162,11 +272,18 @@ func (b *Binding) bindStruct(structPointer interface{}, structValue reflect.Valu
 	if len(bodyBytes) > 0 {
 		err = recv.prebindBody(structPointer, structValue, bodyCodec, bodyBytes)
 	}
+	bodyString := ameda.UnsafeBytesToString(bodyBytes)
 	if err != nil {
-		return
+		if c == nil {
+			return
+		}
+		c.addBodyError(bodyCodec, bodyString, err)
+		err = nil
 	}
-	bodyString := ameda.UnsafeBytesToString(bodyBytes)
-	postForm := req.Request.PostArgs()
+	if c != nil {
+		c.bodyString = bodyString
+	}
+	postForm := getPostForm(req, bodyCodec)
 	queryValues := recv.getQuery(req)
 	reqHeader := &req.Request.Header
 
@@ -184,7 +301,9 @@ func (b *Binding) bindStruct(structPointer interface{}, structValue reflect.Valu
 			case header:
 				found, err = param.bindHeader(info, expr, reqHeader)
 			case form, json, protobuf:
-				if info.paramIn == in(bodyCodec) {
+				if info.paramIn == form && param.fileKind != notFile {
+					found, err = param.bindFile(info, expr, req)
+				} else if info.paramIn == in(bodyCodec) {
 					found, err = param.bindOrRequireBody(info, expr, bodyCodec, bodyString, postForm)
 				} else if info.required {
 					found = false
@@ -193,14 +312,50 @@ func (b *Binding) bindStruct(structPointer interface{}, structValue reflect.Valu
 			case raw_body:
 				err = param.bindRawBody(info, expr, bodyBytes)
 				found = err == nil
+			case user_value:
+				found, err = param.bindUserValue(info, expr, req)
 			case default_val:
 				found, err = param.bindDefaultVal(expr, param.defaultVal)
+			default:
+				// the body of the registered codec
+				if info.paramIn == in(bodyCodec) {
+					found, err = param.checkRequireBody(info, expr, recv.bodyCodecs.get(info.paramIn), bodyBytes)
+				} else if info.required {
+					found = false
+					err = info.requiredError
+				}
 			}
 			if found && err == nil {
+				if c != nil {
+					c.bound[param.fieldSelector] = info
+				}
 				break
 			}
 			if (found || i == len(param.tagInfos)-1) && err != nil {
-				return recv.hasVd, err
+				if c == nil {
+					return recv.hasVd, err
+				}
+				c.add(param, info, err)
+				err = nil
+				break
+			}
+		}
+	}
+	if recv.strict {
+		if !recv.hasBody {
+			bodyCodec, bodyBytes, _ = getBodyInfo(req, recv.bodyCodecs)
+		}
+		unknown := recv.unknownParams(structValue.Type(), req, bodyCodec, bodyBytes)
+		for _, i := range []in{query, form, json} {
+			names := unknown[i]
+			if len(names) == 0 {
+				continue
+			}
+			if c == nil {
+				return recv.hasVd, b.bindErrFactory(strings.Join(names, ", "), "unknown "+i.String()+" parameter")
+			}
+			for _, name := range names {
+				c.addUnknown(i, name, b.bindErrFactory(name, "unknown "+i.String()+" parameter"))
 			}
 		}
 	}
@@ -233,7 +388,12 @@ func (b *Binding) getOrPrepareReceiver(value reflect.Value) (*receiver, error) {
 	}
 	recv = &receiver{
 		params:        make([]*paramInfo, 0, 16),
+		strict:        strictOf(value.Type(), b.config.Strict),
 		looseZeroMode: b.config.LooseZeroMode,
+		notation:      b.config.Notation,
+		bodyCodecs:    b.bodyCodecs,
+		unmarshalers:  b.unmarshalers,
+		keyring:       b.keyring,
 	}
 	var errExprSelector tagexpr.ExprSelector
 	var errMsg string
@@ -246,9 +406,12 @@ func (b *Binding) getOrPrepareReceiver(value reflect.Value) (*receiver, error) {
 			return true
 		}
 
+		if isStrictMarker(fh.StructField().Type) {
+			return true
+		}
 		tagKVs := b.config.parse(fh.StructField())
 		p := recv.getOrAddParam(fh, b.bindErrFactory)
-		tagInfos := [maxIn]*tagInfo{}
+		tagInfos := make(map[in]*tagInfo, len(tagKVs))
 	L:
 		for _, tagKV := range tagKVs {
 			paramIn := undefined
@@ -272,27 +435,39 @@ func (b *Binding) getOrPrepareReceiver(value reflect.Value) (*receiver, error) {
 				paramIn = json
 			case b.config.RawBody:
 				paramIn = raw_body
+			case b.config.UserValue:
+				paramIn = user_value
 			case b.config.defaultVal:
 				paramIn = default_val
 			default:
-				continue L
+				bc := b.bodyCodecs.getByTag(tagKV.name)
+				if bc == nil {
+					continue L
+				}
+				paramIn = bc.paramIn
 			}
 			if paramIn == default_val {
 				tagInfos[paramIn] = &tagInfo{paramIn: default_val, paramName: tagKV.value}
-			} else {
-				tagInfos[paramIn] = tagKV.defaultSplit()
+				continue L
 			}
+			info := tagKV.defaultSplit()
+			if info.optionError != "" {
+				errMsg = info.optionError
+				errExprSelector = tagexpr.ExprSelector(fh.StringSelector())
+				return false
+			}
+			tagInfos[paramIn] = info
 		}
 
-		for i, info := range tagInfos {
-			if info != nil {
+		for _, i := range b.bodyCodecs.sortedAllIn {
+			if info := tagInfos[i]; info != nil {
 				if info.paramIn != default_val && info.paramName == "-" {
-					p.omitIns[in(i)] = true
-					recv.assginIn(in(i), false)
+					p.omitIns[i] = true
+					recv.assginIn(i, false)
 				} else {
-					info.paramIn = in(i)
+					info.paramIn = i
 					p.tagInfos = append(p.tagInfos, info)
-					recv.assginIn(in(i), true)
+					recv.assginIn(i, true)
 				}
 			}
 		}
@@ -315,6 +490,7 @@ func (b *Binding) getOrPrepareReceiver(value reflect.Value) (*receiver, error) {
 					p.tagInfos = append(p.tagInfos, &tagInfo{
 						paramIn:   i,
 						paramName: p.structField.Name,
+						implicit:  true,
 					})
 					recv.assginIn(i, true)
 				}
@@ -322,6 +498,10 @@ func (b *Binding) getOrPrepareReceiver(value reflect.Value) (*receiver, error) {
 		} else {
 			fieldsWithValidTag[fs+tagexpr.FieldSeparator] = true
 		}
+		if p.fileKind != notFile {
+			// the fields of file are not bound from request
+			fieldsWithValidTag[fs+tagexpr.FieldSeparator] = true
+		}
 		if !recv.hasVd {
 			_, recv.hasVd = tagKVs.lookup(b.config.Validator)
 		}
@@ -10,13 +10,13 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
-func getBodyInfo(req *fasthttp.RequestCtx) (codec, []byte, error) {
-	bodyCodec := getBodyCodec(req)
+func getBodyInfo(req *fasthttp.RequestCtx, bcs *bodyCodecs) (codec, []byte, error) {
+	bodyCodec := getBodyCodec(req, bcs)
 	bodyBytes, err := getBody(req, bodyCodec)
 	return bodyCodec, bodyBytes, err
 }
 
-func getBodyCodec(req *fasthttp.RequestCtx) codec {
+func getBodyCodec(req *fasthttp.RequestCtx, bcs *bodyCodecs) codec {
 	ct := req.Request.Header.ContentType()
 	idx := bytes.Index(ct, []byte{';'})
 	if idx != -1 {
@@ -30,6 +30,9 @@ func getBodyCodec(req *fasthttp.RequestCtx) codec {
 	case "application/x-www-form-urlencoded", "multipart/form-data":
 		return bodyForm
 	default:
+		if bc := bcs.getByMediaType(string(bytes.ToLower(ct))); bc != nil {
+			return codec(bc.paramIn)
+		}
 		return bodyUnsupport
 	}
 }
@@ -0,0 +1,231 @@
+package binding
+
+import (
+	"bytes"
+	xmlpkg "encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// BodyCodec the codec of the request body registered by RegisterBodyCodec
+type BodyCodec interface {
+	// Unmarshal decodes the body into the receiver pointer.
+	Unmarshal(bodyBytes []byte, pointer interface{}) error
+	// Exists reports whether the field of the name path exists in the body,
+	// the name path is joined by '.', such as 'a.b'.
+	Exists(bodyBytes []byte, namePath string) bool
+}
+
+type bodyCodecInfo struct {
+	paramIn    in
+	tagName    string
+	mediaTypes []string
+	codec      BodyCodec
+}
+
+// bodyCodecs the body codecs registered in a Binding
+type bodyCodecs struct {
+	list        []*bodyCodecInfo
+	mediaTypes  map[string]*bodyCodecInfo
+	sortedAllIn []in // the order in which to try to bind
+}
+
+func newBodyCodecs() *bodyCodecs {
+	bcs := &bodyCodecs{mediaTypes: make(map[string]*bodyCodecInfo)}
+	bcs.sort()
+	return bcs
+}
+
+func (bcs *bodyCodecs) get(paramIn in) *bodyCodecInfo {
+	if bcs == nil || !paramIn.isBodyCodec() {
+		return nil
+	}
+	for _, bc := range bcs.list {
+		if bc.paramIn == paramIn {
+			return bc
+		}
+	}
+	return nil
+}
+
+func (bcs *bodyCodecs) getByTag(tagName string) *bodyCodecInfo {
+	for _, bc := range bcs.list {
+		if bc.tagName == tagName {
+			return bc
+		}
+	}
+	return nil
+}
+
+func (bcs *bodyCodecs) getByMediaType(mediaType string) *bodyCodecInfo {
+	if bcs == nil {
+		return nil
+	}
+	return bcs.mediaTypes[mediaType]
+}
+
+// sort sets the order in which to try to bind,
+// the registered body codecs are tried after json and before raw_body.
+func (bcs *bodyCodecs) sort() {
+	a := make([]in, 0, int(maxIn)+len(bcs.list))
+	for i := undefined + 1; i < raw_body; i++ {
+		a = append(a, i)
+	}
+	for _, bc := range bcs.list {
+		a = append(a, bc.paramIn)
+	}
+	bcs.sortedAllIn = append(a, raw_body, user_value, default_val)
+}
+
+var builtinBodyMediaTypes = map[string]bool{
+	"application/json":                  true,
+	"application/x-protobuf":            true,
+	"application/x-www-form-urlencoded": true,
+	"multipart/form-data":               true,
+}
+
+// RegisterBodyCodec registers the codec of the body with the media types for the default binding,
+// the fields are bound from the body by the tag name, e.g. `yaml:"$name"` or `yaml:"$name,required"`.
+// NOTE:
+//  It is not concurrent safe, and should be called before binding.
+func RegisterBodyCodec(mediaTypes []string, tagName string, codec BodyCodec) error {
+	return defaultBinding.RegisterBodyCodec(mediaTypes, tagName, codec)
+}
+
+// RegisterBodyCodec registers the codec of the body with the media types,
+// the fields are bound from the body by the tag name, e.g. `yaml:"$name"` or `yaml:"$name,required"`.
+// NOTE:
+//  It is not concurrent safe, and should be called before binding;
+//  The codec replaces the registered one with the same tag name.
+func (b *Binding) RegisterBodyCodec(mediaTypes []string, tagName string, codec BodyCodec) error {
+	if codec == nil {
+		return errors.New("body codec cannot be nil")
+	}
+	if len(mediaTypes) == 0 {
+		return errors.New("body codec must have media types")
+	}
+	bcs := b.bodyCodecs
+	bc := bcs.getByTag(tagName)
+	if bc == nil {
+		for _, name := range b.config.list {
+			if name == tagName {
+				return fmt.Errorf("tag name %q has been used", tagName)
+			}
+		}
+	}
+	a := make([]string, len(mediaTypes))
+	for i, mediaType := range mediaTypes {
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if builtinBodyMediaTypes[mediaType] {
+			return fmt.Errorf("media type %q is built in", mediaType)
+		}
+		if other := bcs.mediaTypes[mediaType]; other != nil && other != bc {
+			return fmt.Errorf("media type %q has been registered by tag name %q", mediaType, other.tagName)
+		}
+		a[i] = mediaType
+	}
+	if bc == nil {
+		bc = &bodyCodecInfo{paramIn: bodyCodecIn(tagName), tagName: tagName}
+		bcs.list = append(bcs.list, bc)
+		bcs.sort()
+		b.config.list = append(b.config.list, tagName)
+	}
+	for _, mediaType := range bc.mediaTypes {
+		delete(bcs.mediaTypes, mediaType)
+	}
+	for _, mediaType := range a {
+		bcs.mediaTypes[mediaType] = bc
+	}
+	bc.mediaTypes = a
+	bc.codec = codec
+	b.resetReceivers()
+	return nil
+}
+
+// the tag names of the parameter positions from maxIn,
+// which are shared by all Binding instances.
+var bodyCodecTags struct {
+	sync.RWMutex
+	names []string
+}
+
+// bodyCodecIn returns the parameter position of the body codec tag name.
+func bodyCodecIn(tagName string) in {
+	bodyCodecTags.Lock()
+	defer bodyCodecTags.Unlock()
+	for i, name := range bodyCodecTags.names {
+		if name == tagName {
+			return maxIn + in(i)
+		}
+	}
+	if int(maxIn)+len(bodyCodecTags.names) >= math.MaxUint8 {
+		panic("binding: too many body codecs")
+	}
+	bodyCodecTags.names = append(bodyCodecTags.names, tagName)
+	return maxIn + in(len(bodyCodecTags.names)-1)
+}
+
+func bodyCodecTagName(i in) (string, bool) {
+	bodyCodecTags.RLock()
+	defer bodyCodecTags.RUnlock()
+	if i < maxIn || int(i-maxIn) >= len(bodyCodecTags.names) {
+		return "", false
+	}
+	return bodyCodecTags.names[i-maxIn], true
+}
+
+// isBodyCodec reports whether the position is the body of a registered codec.
+func (i in) isBodyCodec() bool {
+	return i >= maxIn
+}
+
+// XMLCodec the built-in body codec of 'application/xml' and 'text/xml'
+type XMLCodec struct{}
+
+var _ BodyCodec = XMLCodec{}
+
+// Unmarshal decodes the XML body into the receiver pointer.
+func (XMLCodec) Unmarshal(bodyBytes []byte, pointer interface{}) error {
+	return xmlpkg.Unmarshal(bodyBytes, pointer)
+}
+
+// Exists reports whether the element or attribute of the name path exists in the XML body,
+// the name path is relative to the root element.
+func (XMLCodec) Exists(bodyBytes []byte, namePath string) bool {
+	dec := xmlpkg.NewDecoder(bytes.NewReader(bodyBytes))
+	var depth int
+	var names []string // the element names below the root element
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		switch t := tok.(type) {
+		case xmlpkg.StartElement:
+			depth++
+			if depth > 1 {
+				names = append(names, t.Name.Local)
+			}
+			prefix := strings.Join(names, ".")
+			if prefix == namePath {
+				return true
+			}
+			if prefix != "" {
+				prefix += "."
+			}
+			for _, attr := range t.Attr {
+				if prefix+attr.Name.Local == namePath {
+					return true
+				}
+			}
+		case xmlpkg.EndElement:
+			if depth > 1 {
+				names = names[:len(names)-1]
+			}
+			depth--
+		}
+	}
+}
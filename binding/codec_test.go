@@ -0,0 +1,108 @@
+package binding
+
+import (
+	jsonpkg "encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestBindXML(t *testing.T) {
+	type Item struct {
+		Sku string `xml:"sku,attr,required"`
+	}
+	type Recv struct {
+		ID    int64  `xml:"id,required"`
+		Name  string `xml:"name"`
+		Items []Item `xml:"items>item"`
+		Note  *struct {
+			Text string `xml:"text,required"`
+		} `xml:"note"`
+	}
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.Header.SetContentType("text/xml; charset=utf-8")
+	ctx.Request.SetBodyString(`<order><id>7</id><name>a</name><items><item sku="x"/></items></order>`)
+	recv := new(Recv)
+	err := New(nil).BindAndValidate(recv, ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(7), recv.ID)
+		assert.Equal(t, "a", recv.Name)
+		assert.Equal(t, []Item{{Sku: "x"}}, recv.Items)
+		assert.Nil(t, recv.Note)
+	}
+
+	ctx.Request.Header.SetContentType("application/xml")
+	ctx.Request.SetBodyString(`<order><name>a</name><note></note></order>`)
+	err = New(&Config{CollectAllErrors: true}).BindAndValidate(new(Recv), ctx)
+	errs, ok := err.(Errors)
+	if assert.True(t, ok, err) && assert.Len(t, errs, 2) {
+		assert.Equal(t, "id", errs[0].Field)
+		assert.Equal(t, "xml", errs[0].In)
+		assert.Equal(t, ReasonRequired, errs[0].Reason)
+		assert.Equal(t, "note.text", errs[1].Field)
+		assert.Equal(t, "binding note.text: missing required parameter", errs[1].Error())
+	}
+
+	assert.True(t, XMLCodec{}.Exists([]byte(`<a b="1"><c><d/></c></a>`), "b"))
+	assert.True(t, XMLCodec{}.Exists([]byte(`<a b="1"><c><d/></c></a>`), "c.d"))
+	assert.False(t, XMLCodec{}.Exists([]byte(`<a><c/><d/></a>`), "c.d"))
+}
+
+// kvCodec decodes the body of lines 'key=value'
+type kvCodec struct{}
+
+func (kvCodec) parse(bodyBytes []byte) map[string]string {
+	m := make(map[string]string)
+	for _, line := range strings.Split(string(bodyBytes), "\n") {
+		if kv := strings.SplitN(line, "=", 2); len(kv) == 2 {
+			m[kv[0]] = kv[1]
+		}
+	}
+	return m
+}
+
+func (c kvCodec) Unmarshal(bodyBytes []byte, pointer interface{}) error {
+	b, _ := jsonpkg.Marshal(c.parse(bodyBytes))
+	return jsonpkg.Unmarshal(b, pointer)
+}
+
+func (c kvCodec) Exists(bodyBytes []byte, namePath string) bool {
+	_, ok := c.parse(bodyBytes)[namePath]
+	return ok
+}
+
+func TestRegisterBodyCodec(t *testing.T) {
+	type Recv struct {
+		A string `kv:"a,required" json:"a"`
+		B string `kv:"b,required" json:"b"`
+	}
+	b := New(nil)
+	assert.EqualError(t, b.RegisterBodyCodec([]string{"text/x-kv"}, "query", kvCodec{}), `tag name "query" has been used`)
+	assert.EqualError(t, b.RegisterBodyCodec([]string{"application/json"}, "kv", kvCodec{}), `media type "application/json" is built in`)
+	assert.EqualError(t, b.RegisterBodyCodec([]string{"text/xml"}, "kv", kvCodec{}), `media type "text/xml" has been registered by tag name "xml"`)
+	assert.NoError(t, b.RegisterBodyCodec([]string{"text/x-kv"}, "kv", kvCodec{}))
+
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.Header.SetContentType("text/x-kv")
+	ctx.Request.SetBodyString("a=1\nb=2")
+	recv := new(Recv)
+	if assert.NoError(t, b.BindAndValidate(recv, ctx)) {
+		assert.Equal(t, &Recv{A: "1", B: "2"}, recv)
+	}
+	ctx.Request.SetBodyString("a=1")
+	assert.EqualError(t, b.BindAndValidate(new(Recv), ctx), "binding b: missing required parameter")
+
+	params, err := b.Params(reflect.TypeOf(Recv{}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "kv", params[1].In)
+		assert.Equal(t, "a", params[1].Name)
+		assert.True(t, params[1].Required)
+	}
+	// the kv tag is unknown to other bindings
+	assert.NoError(t, New(nil).BindAndValidate(new(Recv), ctx))
+}
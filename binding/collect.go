@@ -0,0 +1,195 @@
+package binding
+
+import (
+	jsonpkg "encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/bytedance/go-tagexpr"
+	"github.com/bytedance/go-tagexpr/validator"
+	"github.com/tidwall/gjson"
+	"github.com/valyala/fasthttp"
+)
+
+// collector collects the errors of all the failed fields
+type collector struct {
+	errs       Errors
+	bound      map[string]*tagInfo // {fieldSelector:tagInfo}
+	failed     map[string]bool     // {fieldSelector:true}
+	req        *fasthttp.RequestCtx
+	bodyString string
+}
+
+func newCollector() *collector {
+	return &collector{
+		bound:  make(map[string]*tagInfo, 16),
+		failed: make(map[string]bool, 8),
+	}
+}
+
+func (c *collector) add(p *paramInfo, info *tagInfo, err error) {
+	c.failed[p.fieldSelector] = true
+	c.errs = append(c.errs, &FieldError{
+		Field:  info.namePath,
+		In:     info.paramIn.String(),
+		Value:  rawValue(info, c.req, c.bodyString),
+		Reason: info.reason(err),
+		Err:    err,
+	})
+}
+
+func (c *collector) addUnknown(paramIn in, name string, err error) {
+	c.errs = append(c.errs, &FieldError{
+		Field:  name,
+		In:     paramIn.String(),
+		Reason: ReasonUnknownParameter,
+		Err:    err,
+	})
+}
+
+func (c *collector) addBodyError(bodyCodec codec, bodyString string, err error) {
+	e := &FieldError{
+		In:     in(bodyCodec).String(),
+		Reason: ReasonInvalidBody,
+		Err:    err,
+	}
+	if typeErr, ok := err.(*jsonpkg.UnmarshalTypeError); ok && typeErr.Field != "" {
+		e.Field = typeErr.Field
+		e.Value = gjson.Get(bodyString, typeErr.Field).Raw
+		e.Reason = ReasonTypeMismatch
+	}
+	c.errs = append(c.errs, e)
+}
+
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (info *tagInfo) reason(err error) string {
+	switch err {
+	case info.requiredError:
+		return ReasonRequired
+	case info.typeError:
+		return ReasonTypeMismatch
+	case info.contentTypeError:
+		return ReasonUnsupportedContentType
+	case info.cannotError:
+		return ReasonCannotBind
+	case info.fileSizeError:
+		return ReasonFileTooLarge
+	case info.fileTypeError:
+		return ReasonFileTypeNotAllowed
+	default:
+		return ReasonInvalid
+	}
+}
+
+// rawValue returns the raw value of the parameter in request.
+func rawValue(info *tagInfo, req *fasthttp.RequestCtx, bodyString string) string {
+	switch info.paramIn {
+	case path:
+		s, _ := req.UserValue(info.paramName).(string)
+		return s
+	case query:
+		return joinBytes(req.QueryArgs().PeekMulti(info.paramName))
+	case form:
+		return joinBytes(req.PostArgs().PeekMulti(info.paramName))
+	case header:
+		return string(req.Request.Header.Peek(info.paramName))
+	case cookie:
+		return string(req.Request.Header.Cookie(info.paramName))
+	case json:
+		return gjson.Get(bodyString, info.namePath).Raw
+	default:
+		return ""
+	}
+}
+
+func joinBytes(a [][]byte) string {
+	switch len(a) {
+	case 0:
+		return ""
+	case 1:
+		return string(a[0])
+	default:
+		return strings.Join(bytesSliceToStringSlice(a), ",")
+	}
+}
+
+// bindAll binds all the parameters and validates them if validate is true,
+// returns Errors of all the failed fields.
+func (b *Binding) bindAll(pointer interface{}, req *fasthttp.RequestCtx, validate bool) error {
+	elemValue, err := b.receiverValueOf(pointer)
+	if err != nil {
+		return err
+	}
+	if elemValue.Kind() != reflect.Struct {
+		hasVd, err := b.bindNonstruct(pointer, elemValue, req)
+		if err != nil || !validate || !hasVd {
+			return err
+		}
+		return b.validateAll(elemValue, nil).err()
+	}
+	c := newCollector()
+	c.req = req
+	hasVd, err := b.bindStruct(pointer, elemValue, req, c)
+	if err != nil {
+		return err
+	}
+	if validate && hasVd {
+		c.errs = append(c.errs, b.validateAll(elemValue, c)...)
+	}
+	return c.errs.err()
+}
+
+// validateAll validates all the fields of value,
+// the fields failed to bind are skipped if c is not nil.
+func (b *Binding) validateAll(value interface{}, c *collector) Errors {
+	var errs Errors
+	b.vd.VM().RunAny(value, func(te *tagexpr.TagExpr, err error) error {
+		if err != nil {
+			errs = append(errs, &FieldError{Reason: ReasonInvalid, Err: err})
+			return nil
+		}
+		nilParentFields := make(map[string]bool, 16)
+		return te.Range(func(eh *tagexpr.ExprHandler) error {
+			selector := eh.StringSelector()
+			if strings.Contains(selector, tagexpr.ExprNameSeparator) || eh.EvalBool() {
+				return nil
+			}
+			// ignore this error if the value of the parent is nil
+			if pfs, ok := eh.ExprSelector().ParentField(); ok {
+				if nilParentFields[pfs] {
+					return nil
+				}
+				if fh, ok := te.Field(pfs); ok {
+					v := fh.Value(false)
+					if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+						nilParentFields[pfs] = true
+						return nil
+					}
+				}
+			}
+			e := &FieldError{Field: eh.Path(), Reason: ReasonInvalid}
+			// only the fields of the top struct are bound from request
+			if c != nil && eh.Path() == selector {
+				fs := eh.ExprSelector().Field()
+				if c.failed[fs] {
+					return nil
+				}
+				if info := c.bound[fs]; info != nil {
+					e.Field = info.namePath
+					e.In = info.paramIn.String()
+					e.Value = rawValue(info, c.req, c.bodyString)
+				}
+			}
+			e.Err = b.vdErrFactory(eh.Path(), te.EvalString(selector+tagexpr.ExprNameSeparator+validator.ErrMsgExprName))
+			errs = append(errs, e)
+			return nil
+		})
+	})
+	return errs
+}
@@ -0,0 +1,49 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCollectAllErrors(t *testing.T) {
+	type Recv struct {
+		A int    `query:"a,required"`
+		B int    `query:"b"`
+		C string `header:"X-C,required"`
+		D int    `query:"d" vd:"$>10"`
+		E int    `json:"e"`
+		F string `json:"f,required"`
+		G string `json:"g" vd:"len($)>3"`
+	}
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.SetRequestURI("/?b=x&d=5")
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.Header.SetContentType("application/json")
+	ctx.Request.SetBodyString(`{"e":"y","g":"ab"}`)
+
+	err := New(&Config{CollectAllErrors: true}).BindAndValidate(new(Recv), ctx)
+	errs, ok := err.(Errors)
+	if !assert.True(t, ok, err) {
+		return
+	}
+	type brief struct{ Field, In, Value, Reason string }
+	var got []brief
+	for _, e := range errs {
+		got = append(got, brief{e.Field, e.In, e.Value, e.Reason})
+	}
+	assert.Equal(t, []brief{
+		{"e", "json", `"y"`, ReasonTypeMismatch},
+		{"A", "query", "", ReasonRequired},
+		{"B", "query", "x", ReasonTypeMismatch},
+		{"C", "header", "", ReasonRequired},
+		{"f", "json", "", ReasonRequired},
+		{"D", "query", "5", ReasonInvalid},
+		{"g", "json", `"ab"`, ReasonInvalid},
+	}, got)
+
+	err = New(nil).BindAndValidate(new(Recv), ctx)
+	_, ok = err.(Errors)
+	assert.False(t, ok)
+}
@@ -11,10 +11,12 @@ var defaultBinding = New(nil)
 //  header tag name is 'header';
 //  cookie tag name is 'cookie';
 //  raw_body tag name is 'raw_body';
+//  user value tag name is 'ctx';
 //  form tag name is 'form';
 //  validator tag name is 'vd';
 //  protobuf tag name is 'protobuf';
 //  json tag name is 'json';
+//  xml tag name is 'xml';
 //  LooseZeroMode is false.
 func Default() *Binding {
 	return defaultBinding
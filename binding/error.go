@@ -1,5 +1,10 @@
 package binding
 
+import (
+	jsonpkg "encoding/json"
+	"strings"
+)
+
 // Error validate error
 type Error struct {
 	ErrType, FailField, Msg string
@@ -22,3 +27,70 @@ func newDefaultErrorFactory(errType string) func(string, string) error {
 		}
 	}
 }
+
+// Reason codes of FieldError
+const (
+	// ReasonRequired the required parameter is missing
+	ReasonRequired = "required"
+	// ReasonTypeMismatch the parameter cannot be converted to the field type
+	ReasonTypeMismatch = "type_mismatch"
+	// ReasonUnsupportedContentType the body content type does not support the parameter
+	ReasonUnsupportedContentType = "unsupported_content_type"
+	// ReasonCannotBind the parameter cannot be bound to the field
+	ReasonCannotBind = "cannot_bind"
+	// ReasonInvalidBody the body cannot be decoded
+	ReasonInvalidBody = "invalid_body"
+	// ReasonInvalid the parameter fails the validation
+	ReasonInvalid = "invalid"
+	// ReasonFileTooLarge the uploaded file exceeds the max size
+	ReasonFileTooLarge = "file_too_large"
+	// ReasonFileTypeNotAllowed the MIME type of the uploaded file is not allowed
+	ReasonFileTypeNotAllowed = "file_type_not_allowed"
+	// ReasonUnknownParameter the parameter is not bound to any field in strict mode
+	ReasonUnknownParameter = "unknown_parameter"
+)
+
+// FieldError the binding or validating error of a field
+type FieldError struct {
+	// Field the name path of the field, such as 'a.b'
+	Field string `json:"field"`
+	// In the parameter position, such as path, query, header, cookie, form, json, protobuf, xml, raw_body and ctx
+	In string `json:"in,omitempty"`
+	// Value the rejected raw value
+	Value string `json:"value,omitempty"`
+	// Reason the machine-readable reason code, such as ReasonRequired
+	Reason string `json:"reason"`
+	// Err the original error
+	Err error `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, the message of Err is encoded as 'message'.
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	type fieldError FieldError
+	return jsonpkg.Marshal(struct {
+		*fieldError
+		Message string `json:"message"`
+	}{(*fieldError)(e), e.Error()})
+}
+
+// Error implements error interface.
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors the binding and validating errors of all fields
+type Errors []*FieldError
+
+// Error implements error interface.
+func (e Errors) Error() string {
+	a := make([]string, len(e))
+	for i, err := range e {
+		a[i] = err.Error()
+	}
+	return strings.Join(a, "; ")
+}
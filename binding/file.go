@@ -0,0 +1,187 @@
+package binding
+
+import (
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/go-tagexpr"
+	"github.com/valyala/fasthttp"
+)
+
+// File the uploaded file of multipart form, which can be read as io.Reader.
+// NOTE:
+//  The file is opened on the first read, close it after reading.
+type File struct {
+	*multipart.FileHeader
+	file multipart.File
+}
+
+// Read implements io.Reader.
+func (f *File) Read(p []byte) (int, error) {
+	if f.file == nil {
+		file, err := f.FileHeader.Open()
+		if err != nil {
+			return 0, err
+		}
+		f.file = file
+	}
+	return f.file.Read(p)
+}
+
+// Close closes the file if it is opened.
+func (f *File) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+type fileKind uint8
+
+const (
+	notFile fileKind = iota
+	fileHeader
+	fileHeaders
+	fileReader
+	fileReaders
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf(new(multipart.FileHeader))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader{})
+	fileReaderType  = reflect.TypeOf(new(File))
+	fileReadersType = reflect.TypeOf([]*File{})
+)
+
+func fileKindOf(t reflect.Type) fileKind {
+	switch t {
+	case fileHeaderType:
+		return fileHeader
+	case fileHeadersType:
+		return fileHeaders
+	case fileReaderType:
+		return fileReader
+	case fileReadersType:
+		return fileReaders
+	default:
+		return notFile
+	}
+}
+
+const (
+	tagOptMaxSize = "maxsize="
+	tagOptMIME    = "mime="
+)
+
+// parseSize parses the size such as '1024', '512KB', '2MB' and '1GB'.
+func parseSize(s string) (int64, bool) {
+	unit := int64(1)
+	upper := strings.ToUpper(s)
+	for _, u := range []struct {
+		suffix string
+		unit   int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(upper, u.suffix) {
+			unit = u.unit
+			s = s[:len(s)-len(u.suffix)]
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n * unit, true
+}
+
+// matchMIME reports whether the content type of the file matches one of the allowed MIME types,
+// which supports wildcard subtype such as 'image/*'.
+func matchMIME(allowed []string, contentType string) bool {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	for _, m := range allowed {
+		if m == contentType {
+			return true
+		}
+		if strings.HasSuffix(m, "/*") && strings.HasPrefix(contentType, m[:len(m)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindFile binds the uploaded files of multipart form.
+// NOTE:
+//  The 'Content-Type' of the file part declared by the client is checked against the allowed MIME types.
+func (p *paramInfo) bindFile(info *tagInfo, expr *tagexpr.TagExpr, req *fasthttp.RequestCtx) (bool, error) {
+	var fhs []*multipart.FileHeader
+	if mf, err := req.MultipartForm(); err == nil {
+		fhs = mf.File[info.paramName]
+	}
+	if len(fhs) == 0 {
+		if info.required {
+			return false, info.requiredError
+		}
+		return false, nil
+	}
+	for _, fh := range fhs {
+		if info.fileMaxSize > 0 && fh.Size > info.fileMaxSize {
+			return true, info.fileSizeError
+		}
+		if len(info.fileMIMEs) > 0 && !matchMIME(info.fileMIMEs, fh.Header.Get("Content-Type")) {
+			return true, info.fileTypeError
+		}
+	}
+	v, err := p.getField(expr, false)
+	if err != nil || !v.IsValid() {
+		return false, err
+	}
+	switch p.fileKind {
+	case fileHeader:
+		v.Set(reflect.ValueOf(fhs[0]))
+	case fileHeaders:
+		v.Set(reflect.ValueOf(fhs))
+	case fileReader:
+		v.Set(reflect.ValueOf(&File{FileHeader: fhs[0]}))
+	case fileReaders:
+		files := make([]*File, len(fhs))
+		for i, fh := range fhs {
+			files[i] = &File{FileHeader: fh}
+		}
+		v.Set(reflect.ValueOf(files))
+	}
+	return true, nil
+}
+
+// getPostForm returns the values of the form body, including the text values of multipart form.
+func getPostForm(req *fasthttp.RequestCtx, bodyCodec codec) *fasthttp.Args {
+	postForm := req.Request.PostArgs()
+	if bodyCodec == bodyForm && postForm.Len() == 0 {
+		if args := multipartValues(req); args != nil {
+			postForm = args
+		}
+	}
+	return postForm
+}
+
+// multipartValues returns the values of multipart form as args,
+// or nil if the body is not multipart form.
+func multipartValues(req *fasthttp.RequestCtx) *fasthttp.Args {
+	mf, err := req.MultipartForm()
+	if err != nil {
+		return nil
+	}
+	args := new(fasthttp.Args)
+	for k, a := range mf.Value {
+		for _, v := range a {
+			args.Add(k, v)
+		}
+	}
+	return args
+}
@@ -0,0 +1,85 @@
+package binding
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func newMultipartCtx(t *testing.T, values map[string]string, files map[string][]string) *fasthttp.RequestCtx {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range values {
+		assert.NoError(t, w.WriteField(k, v))
+	}
+	for k, a := range files {
+		// a: content type, content, content type, content...
+		for i := 0; i < len(a); i += 2 {
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", `form-data; name="`+k+`"; filename="f.bin"`)
+			h.Set("Content-Type", a[i])
+			part, err := w.CreatePart(h)
+			assert.NoError(t, err)
+			part.Write([]byte(a[i+1]))
+		}
+	}
+	assert.NoError(t, w.Close())
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.Header.SetContentType(w.FormDataContentType())
+	ctx.Request.SetBody(buf.Bytes())
+	return ctx
+}
+
+func TestBindFile(t *testing.T) {
+	type Recv struct {
+		Title  string                  `form:"title,required"`
+		Avatar *multipart.FileHeader   `form:"avatar,required,maxsize=1KB,mime=image/*"`
+		Docs   []*multipart.FileHeader `form:"docs"`
+		Upload *File                   `form:"upload"`
+		Files  []*File
+	}
+	ctx := newMultipartCtx(t, map[string]string{"title": "hi"}, map[string][]string{
+		"avatar": {"image/png", "png"},
+		"docs":   {"text/plain", "a", "text/plain", "b"},
+		"upload": {"application/octet-stream", "content"},
+		"Files":  {"text/plain", "c"},
+	})
+	recv := new(Recv)
+	if assert.NoError(t, New(nil).BindAndValidate(recv, ctx)) {
+		assert.Equal(t, "hi", recv.Title)
+		assert.Equal(t, int64(3), recv.Avatar.Size)
+		assert.Len(t, recv.Docs, 2)
+		b, err := ioutil.ReadAll(recv.Upload)
+		assert.NoError(t, err)
+		assert.Equal(t, "content", string(b))
+		assert.NoError(t, recv.Upload.Close())
+		assert.Len(t, recv.Files, 1)
+	}
+
+	ctx = newMultipartCtx(t, nil, map[string][]string{
+		"avatar": {"application/pdf", string(make([]byte, 2048))},
+	})
+	err := New(&Config{CollectAllErrors: true}).BindAndValidate(new(Recv), ctx)
+	errs, ok := err.(Errors)
+	if assert.True(t, ok, err) && assert.Len(t, errs, 2) {
+		assert.Equal(t, ReasonRequired, errs[0].Reason)
+		assert.Equal(t, ReasonFileTooLarge, errs[1].Reason)
+		assert.Equal(t, "binding Avatar: file size exceeds 1024 bytes", errs[1].Error())
+	}
+	ctx = newMultipartCtx(t, map[string]string{"title": "hi"}, map[string][]string{
+		"avatar": {"application/pdf", "pdf"},
+	})
+	assert.EqualError(t, New(nil).BindAndValidate(new(Recv), ctx), "binding Avatar: file type is not one of image/*")
+
+	type BadSize struct {
+		Avatar *multipart.FileHeader `form:"avatar,maxsize=10mb!"`
+	}
+	err = New(nil).BindAndValidate(new(BadSize), ctx)
+	assert.EqualError(t, err, `binding Avatar: invalid file size "10mb!", expect the form such as 1024, 512KB, 2MB or 1GB`)
+}
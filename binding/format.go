@@ -0,0 +1,128 @@
+package binding
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	tagOptLayout    = "layout="
+	tagOptUnix      = "unix"
+	tagOptUnixMilli = "unixmilli"
+)
+
+// the named layouts of the option 'layout=', the spaces in the tag are removed,
+// so the layout with spaces can only be set by name.
+var namedLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+// layoutRefTime the time formatted to check whether the layout has any element
+var layoutRefTime = time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+// parseLayout returns the named layout or the layout itself,
+// ok is false if it is neither a name nor a layout with any element, such as a misspelled name.
+func parseLayout(layout string) (string, bool) {
+	if s, ok := namedLayouts[layout]; ok {
+		return s, true
+	}
+	return layout, layout != "" && layoutRefTime.Format(layout) != layout
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isFormattedType reports whether the type is parsed by setFormattedValue.
+func isFormattedType(t reflect.Type, info *tagInfo, unmarshalers *typeUnmarshalers) bool {
+	switch {
+	case t == timeType:
+		return info != nil && (info.timeLayout != "" || info.timeUnix > 0)
+	case t == durationType:
+		return true
+	case unmarshalers.get(t) != nil:
+		return false
+	default:
+		return reflect.PtrTo(t).Implements(textUnmarshalerType)
+	}
+}
+
+// setFormattedValue sets the string to the value of the time with the layout or unix option,
+// time.Duration, or the type implementing encoding.TextUnmarshaler,
+// returns handled=false if the type is not one of them.
+func setFormattedValue(v reflect.Value, s string, c unmarshalCtx) (handled bool, err error) {
+	t := v.Type()
+	info := c.info
+	if !isFormattedType(t, info, c.unmarshalers) {
+		return false, nil
+	}
+	if s == "" && c.looseZeroMode {
+		v.Set(reflect.Zero(t))
+		return true, nil
+	}
+	switch t {
+	case timeType:
+		var tm time.Time
+		if info.timeUnix > 0 {
+			var i int64
+			i, err = strconv.ParseInt(s, 10, 64)
+			if info.timeUnix == time.Second {
+				tm = time.Unix(i, 0)
+			} else {
+				tm = time.Unix(i/1e3, i%1e3*1e6)
+			}
+		} else {
+			tm, err = time.Parse(info.timeLayout, s)
+		}
+		if err != nil {
+			return true, errMismatch
+		}
+		v.Set(reflect.ValueOf(tm))
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			// the integer is nanoseconds
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return true, errMismatch
+			}
+			d = time.Duration(i)
+		}
+		v.SetInt(int64(d))
+	default:
+		p := reflect.New(t)
+		if p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)) != nil {
+			return true, errMismatch
+		}
+		v.Set(p.Elem())
+	}
+	return true, nil
+}
+
+// formattedSlice converts the strings to the slice of the formatted element type.
+func formattedSlice(t reflect.Type, a []string, c unmarshalCtx) (reflect.Value, error) {
+	s := reflect.MakeSlice(t, len(a), len(a))
+	for i, str := range a {
+		if _, err := setFormattedValue(s.Index(i), str, c); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return s, nil
+}
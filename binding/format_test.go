@@ -0,0 +1,69 @@
+package binding
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type upperText string
+
+func (u *upperText) UnmarshalText(text []byte) error {
+	*u = upperText(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestFormattedValue(t *testing.T) {
+	type Recv struct {
+		Since    time.Time       `query:"since,layout=2006-01-02"`
+		At       time.Time       `query:"at,layout=DateTime"`
+		Unix     time.Time       `query:"unix,unix"`
+		Milli    *time.Time      `query:"milli,unixmilli"`
+		Days     []time.Time     `query:"days,layout=DateOnly,style=comma"`
+		RFC3339  time.Time       `query:"rfc3339"`
+		Timeout  time.Duration   `query:"timeout"`
+		Nanos    time.Duration   `query:"nanos"`
+		Timeouts []time.Duration `query:"timeouts"`
+		IP       net.IP          `query:"ip"`
+		Upper    upperText       `query:"upper"`
+		Empty    time.Duration   `query:"empty"`
+	}
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.SetRequestURI("/?since=2020-01-02&at=2020-01-02%2003:04:05&unix=1577934245&milli=1577934245123" +
+		"&days=2020-01-02,2020-01-03&rfc3339=2020-01-02T03:04:05Z&timeout=90s&nanos=100" +
+		"&timeouts=1m&timeouts=1h&ip=127.0.0.1&upper=abc&empty=")
+	recv := new(Recv)
+	err := New(nil).SetLooseZeroMode(true).Bind(recv, ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), recv.Since)
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), recv.At)
+	assert.True(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC).Equal(recv.Unix))
+	assert.True(t, time.Date(2020, 1, 2, 3, 4, 5, 123e6, time.UTC).Equal(*recv.Milli))
+	assert.Equal(t, []time.Time{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)}, recv.Days)
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), recv.RFC3339)
+	assert.Equal(t, 90*time.Second, recv.Timeout)
+	assert.Equal(t, time.Duration(100), recv.Nanos)
+	assert.Equal(t, []time.Duration{time.Minute, time.Hour}, recv.Timeouts)
+	assert.Equal(t, "127.0.0.1", recv.IP.String())
+	assert.Equal(t, upperText("ABC"), recv.Upper)
+	assert.Equal(t, time.Duration(0), recv.Empty)
+
+	ctx.Request.SetRequestURI("/?since=2020/01/02")
+	err = New(nil).Bind(new(Recv), ctx)
+	assert.EqualError(t, err, "binding Since: parameter type does not match binding data")
+	ctx.Request.SetRequestURI("/?timeout=abc")
+	err = New(nil).Bind(new(Recv), ctx)
+	assert.EqualError(t, err, "binding Timeout: parameter type does not match binding data")
+
+	type BadLayout struct {
+		Since time.Time `query:"since,layout=DateOnlyy"`
+	}
+	err = New(nil).Bind(new(BadLayout), ctx)
+	assert.EqualError(t, err, `binding Since: unknown time layout "DateOnlyy"`)
+}
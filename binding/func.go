@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 )
 
@@ -21,20 +22,50 @@ func ResetJSONUnmarshaler(fn JSONUnmarshaler) {
 	jsonUnmarshalFunc = fn
 }
 
-var typeUnmarshalFuncs = make(map[reflect.Type]func(string, bool) (reflect.Value, error))
+// FieldContext the context of the field whose parameter is being unmarshaled.
+type FieldContext struct {
+	// Field the struct field,
+	// it is the inner field for the nested parameters such as 'filter[status]=open'.
+	Field reflect.StructField
+	// In the position of the parameter, such as "path", "query", "header", "cookie", "form" and "ctx"
+	In string
+	// Name the parameter name
+	Name string
+	// Options the tag options following the parameter name, such as "required" and "style=comma"
+	Options []string
+}
 
-// MustRegTypeUnmarshal registers unmarshalor function of type.
-// NOTE:
-//  panic if exist error.
-func MustRegTypeUnmarshal(t reflect.Type, fn func(v string, emptyAsZero bool) (reflect.Value, error)) {
-	err := RegTypeUnmarshal(t, fn)
-	if err != nil {
-		panic(err)
+// TypeUnmarshalFunc unmarshals the parameter string to the value of the registered type.
+type TypeUnmarshalFunc func(ctx *FieldContext, v string, emptyAsZero bool) (reflect.Value, error)
+
+// typeUnmarshalers the registry of the type unmarshalor functions, which is safe for concurrent use,
+// the functions of the parent are used if the type is not registered.
+type typeUnmarshalers struct {
+	lock   sync.RWMutex
+	funcs  map[reflect.Type]TypeUnmarshalFunc
+	parent *typeUnmarshalers
+}
+
+func newTypeUnmarshalers(parent *typeUnmarshalers) *typeUnmarshalers {
+	return &typeUnmarshalers{
+		funcs:  make(map[reflect.Type]TypeUnmarshalFunc),
+		parent: parent,
 	}
 }
 
-// RegTypeUnmarshal registers unmarshalor function of type.
-func RegTypeUnmarshal(t reflect.Type, fn func(v string, emptyAsZero bool) (reflect.Value, error)) error {
+func (u *typeUnmarshalers) get(t reflect.Type) TypeUnmarshalFunc {
+	for ; u != nil; u = u.parent {
+		u.lock.RLock()
+		fn := u.funcs[t]
+		u.lock.RUnlock()
+		if fn != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+func (u *typeUnmarshalers) reg(t reflect.Type, fn TypeUnmarshalFunc) error {
 	// check
 	switch t.Kind() {
 	case reflect.String, reflect.Bool,
@@ -46,7 +77,7 @@ func RegTypeUnmarshal(t reflect.Type, fn func(v string, emptyAsZero bool) (refle
 		return errors.New("registration type cannot be a pointer type")
 	}
 	// test
-	vv, err := fn("", true)
+	vv, err := fn(new(FieldContext), "", true)
 	if err != nil {
 		return fmt.Errorf("test fail: %s", err)
 	}
@@ -54,10 +85,33 @@ func RegTypeUnmarshal(t reflect.Type, fn func(v string, emptyAsZero bool) (refle
 		return fmt.Errorf("test fail: expect return value type is %s, but got %s", t.String(), tt.String())
 	}
 
-	typeUnmarshalFuncs[t] = fn
+	u.lock.Lock()
+	u.funcs[t] = fn
+	u.lock.Unlock()
 	return nil
 }
 
+var globalTypeUnmarshalers = newTypeUnmarshalers(nil)
+
+// MustRegTypeUnmarshal registers unmarshalor function of type.
+// NOTE:
+//  panic if exist error.
+func MustRegTypeUnmarshal(t reflect.Type, fn func(v string, emptyAsZero bool) (reflect.Value, error)) {
+	err := RegTypeUnmarshal(t, fn)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegTypeUnmarshal registers unmarshalor function of type for all the Binding instances.
+// NOTE:
+//  The function registered by Binding.RegTypeUnmarshal takes precedence over it.
+func RegTypeUnmarshal(t reflect.Type, fn func(v string, emptyAsZero bool) (reflect.Value, error)) error {
+	return globalTypeUnmarshalers.reg(t, func(_ *FieldContext, v string, emptyAsZero bool) (reflect.Value, error) {
+		return fn(v, emptyAsZero)
+	})
+}
+
 func init() {
 	MustRegTypeUnmarshal(reflect.TypeOf(time.Time{}), func(v string, emptyAsZero bool) (reflect.Value, error) {
 		if v == "" && emptyAsZero {
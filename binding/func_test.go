@@ -0,0 +1,132 @@
+package binding
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type money struct {
+	Cents    int64
+	Currency string
+}
+
+var moneyType = reflect.TypeOf(money{})
+
+// unmarshalMoney parses "12.34" in query, and "USD 1234" in cents with the currency in header.
+func unmarshalMoney(ctx *FieldContext, v string, emptyAsZero bool) (reflect.Value, error) {
+	if v == "" {
+		return reflect.ValueOf(money{}), nil
+	}
+	var m money
+	switch ctx.In {
+	case "header":
+		a := strings.SplitN(v, " ", 2)
+		if len(a) != 2 {
+			return reflect.Value{}, errors.New("invalid money")
+		}
+		cents, err := strconv.ParseInt(a[1], 10, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		m = money{Cents: cents, Currency: a[0]}
+	default:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		m = money{Cents: int64(f*100 + 0.5), Currency: "CNY"}
+		for _, opt := range ctx.Options {
+			if strings.HasPrefix(opt, "currency=") {
+				m.Currency = opt[len("currency="):]
+			}
+		}
+	}
+	return reflect.ValueOf(m), nil
+}
+
+func TestBinding_RegTypeUnmarshal(t *testing.T) {
+	type Filter struct {
+		Max money `json:"max"`
+	}
+	type Recv struct {
+		Price  money   `query:"price,currency=USD"`
+		Fee    money   `header:"X-Fee"`
+		Prices []money `query:"prices"`
+		Filter Filter  `query:"filter"`
+	}
+	var fields []string
+	var lock sync.Mutex
+	b := New(&Config{Notation: NotationBracket}).MustRegTypeUnmarshal(moneyType, func(ctx *FieldContext, v string, emptyAsZero bool) (reflect.Value, error) {
+		if ctx.Field.Name != "" {
+			lock.Lock()
+			fields = append(fields, ctx.In+":"+ctx.Name+":"+ctx.Field.Name)
+			lock.Unlock()
+		}
+		return unmarshalMoney(ctx, v, emptyAsZero)
+	})
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.SetRequestURI("/?price=1.5&prices=1&prices=2.25&filter[max]=3")
+	ctx.Request.Header.Set("X-Fee", "EUR 250")
+	recv := new(Recv)
+	if assert.NoError(t, b.Bind(recv, ctx)) {
+		assert.Equal(t, money{Cents: 150, Currency: "USD"}, recv.Price)
+		assert.Equal(t, money{Cents: 250, Currency: "EUR"}, recv.Fee)
+		assert.Equal(t, []money{{Cents: 100, Currency: "CNY"}, {Cents: 225, Currency: "CNY"}}, recv.Prices)
+		assert.Equal(t, money{Cents: 300, Currency: "CNY"}, recv.Filter.Max)
+	}
+	assert.Equal(t, []string{
+		"query:price:Price",
+		"header:X-Fee:Fee",
+		"query:prices:Prices",
+		"query:prices:Prices",
+		"query:filter:Max",
+	}, fields)
+
+	ctx.Request.Header.Set("X-Fee", "250")
+	err := b.Bind(new(Recv), ctx)
+	assert.EqualError(t, err, "binding Fee: parameter type does not match binding data")
+
+	// the other bindings do not use it
+	err = New(nil).Bind(new(Recv), ctx)
+	assert.EqualError(t, err, "binding Price: parameter type does not match binding data")
+
+	err = b.RegTypeUnmarshal(reflect.TypeOf(0), unmarshalMoney)
+	assert.EqualError(t, err, "registration type cannot be a basic type")
+	err = b.RegTypeUnmarshal(reflect.TypeOf(&money{}), unmarshalMoney)
+	assert.EqualError(t, err, "registration type cannot be a pointer type")
+}
+
+func TestBinding_RegTypeUnmarshalConcurrently(t *testing.T) {
+	type Recv struct {
+		Price money `query:"price"`
+	}
+	b := New(nil)
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.SetRequestURI("/?price=1")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			b.MustRegTypeUnmarshal(moneyType, unmarshalMoney)
+		}()
+		go func() {
+			defer wg.Done()
+			var req fasthttp.RequestCtx
+			ctx.Request.CopyTo(&req.Request)
+			_ = b.Bind(new(Recv), &req)
+		}()
+	}
+	wg.Wait()
+	recv := new(Recv)
+	if assert.NoError(t, b.Bind(recv, ctx)) {
+		assert.Equal(t, money{Cents: 100, Currency: "CNY"}, recv.Price)
+	}
+}
@@ -0,0 +1,195 @@
+package binding
+
+import (
+	"bytes"
+	jsonpkg "encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/henrylee2cn/ameda"
+	"github.com/tidwall/gjson"
+	"github.com/valyala/fasthttp"
+)
+
+// Binder the struct binds itself from the request without reflection,
+// the method is usually generated by 'github.com/henrylee2cn/rester/cmd/rester-bindgen'.
+// NOTE:
+//  Binding uses it in place of the reflective binding only if the config is the one assumed by the generated code,
+//  that is the default tag names, LooseZeroMode, NotationNone, not Strict, not CollectAllErrors,
+//  and no body codec registered other than XML;
+//  The validation is still done by the 'vd' tags after binding.
+type Binder interface {
+	BindRester(ctx *fasthttp.RequestCtx) error
+}
+
+// binderCompatible reports whether the config is the one assumed by the generated binders.
+func (b *Binding) binderCompatible() bool {
+	c := &b.config
+	if !c.LooseZeroMode || c.Strict || c.CollectAllErrors || c.Notation != NotationNone {
+		return false
+	}
+	if c.PathParam != defaultTagPath || c.Query != defaultTagQuery || c.Header != defaultTagHeader ||
+		c.Cookie != defaultTagCookie || c.RawBody != defaultTagRawbody || c.FormBody != defaultTagForm ||
+		c.UserValue != defaultTagUserValue || c.Validator != defaultTagValidator {
+		return false
+	}
+	if len(b.bodyCodecs.list) != 1 {
+		return false
+	}
+	bc := b.bodyCodecs.list[0]
+	_, isXML := bc.codec.(XMLCodec)
+	return isXML && bc.tagName == tagXML && len(bc.mediaTypes) == 2 &&
+		bc.mediaTypes[0] == "application/xml" && bc.mediaTypes[1] == "text/xml"
+}
+
+// bindBinder binds the struct by its generated method.
+func (b *Binding) bindBinder(binder Binder, structValue reflect.Value, req *fasthttp.RequestCtx) (hasVd bool, err error) {
+	recv, err := b.getOrPrepareReceiver(structValue)
+	if err != nil {
+		return
+	}
+	err = binder.BindRester(req)
+	if e, ok := err.(*Error); ok && e.ErrType == bindErrType {
+		err = b.bindErrFactory(e.FailField, e.Msg)
+	}
+	return recv.hasVd, err
+}
+
+// GenRequest the request read by the generated binders.
+// NOTE:
+//  It is used by the generated code, do not use it directly.
+type GenRequest struct {
+	ctx        *fasthttp.RequestCtx
+	bodyCodec  codec
+	bodyString string
+	postForm   *fasthttp.Args
+}
+
+// NewGenRequest reads the body if the struct has the body fields,
+// and decodes the JSON, protobuf or XML body into the struct pointer like the reflective binding.
+// NOTE:
+//  It is used by the generated code, do not use it directly.
+func NewGenRequest(ctx *fasthttp.RequestCtx, pointer interface{}, hasBody bool) (GenRequest, error) {
+	r := GenRequest{ctx: ctx}
+	if !hasBody {
+		r.postForm = ctx.Request.PostArgs()
+		return r, nil
+	}
+	ct := ctx.Request.Header.ContentType()
+	if idx := bytes.IndexByte(ct, ';'); idx != -1 {
+		ct = bytes.TrimRight(ct[:idx], " ")
+	}
+	r.bodyCodec = getBodyCodec(ctx, nil)
+	bodyBytes := ctx.Request.Body()
+	r.bodyString = ameda.UnsafeBytesToString(bodyBytes)
+	r.postForm = getPostForm(ctx, r.bodyCodec)
+	if len(bodyBytes) == 0 {
+		return r, nil
+	}
+	switch r.bodyCodec {
+	case bodyJSON:
+		return r, bindJSON(pointer, bodyBytes)
+	case bodyProtobuf:
+		return r, bindProtobuf(pointer, bodyBytes)
+	case bodyUnsupport:
+		switch strings.ToLower(ameda.UnsafeBytesToString(ct)) {
+		case "application/xml", "text/xml":
+			return r, XMLCodec{}.Unmarshal(bodyBytes, pointer)
+		}
+	}
+	return r, nil
+}
+
+// Path returns the path parameter, or nil if not found.
+func (r *GenRequest) Path(name string) []string {
+	s, ok := r.ctx.UserValue(name).(string)
+	if !ok {
+		return nil
+	}
+	return []string{s}
+}
+
+// Query returns the query values split by the array style, or nil if not found.
+func (r *GenRequest) Query(name, style string) []string {
+	return peekArgs(r.ctx.QueryArgs(), name, style)
+}
+
+// Header returns the header value, or nil if not found.
+func (r *GenRequest) Header(name string) []string {
+	v := r.ctx.Request.Header.Peek(name)
+	if len(v) == 0 {
+		return nil
+	}
+	return []string{ameda.UnsafeBytesToString(v)}
+}
+
+// Cookie returns the cookie value, or nil if not found.
+func (r *GenRequest) Cookie(name string) []string {
+	v := r.ctx.Request.Header.Cookie(name)
+	if len(v) == 0 {
+		return nil
+	}
+	return []string{ameda.UnsafeBytesToString(v)}
+}
+
+// Form returns the form values split by the array style,
+// ok is false if the body is not form.
+func (r *GenRequest) Form(name, style string) (values []string, ok bool) {
+	if r.bodyCodec != bodyForm {
+		return nil, false
+	}
+	return peekArgs(r.postForm, name, style), true
+}
+
+// JSON reports whether the field of the name path is found in the JSON body,
+// ok is false if the body is not JSON.
+func (r *GenRequest) JSON(namePath string) (found bool, ok bool) {
+	if r.bodyCodec != bodyJSON {
+		return false, false
+	}
+	if gjson.Get(r.bodyString, namePath).Exists() {
+		return true, true
+	}
+	idx := strings.LastIndex(namePath, ".")
+	// There should be a superior but it is empty, no error is reported
+	return idx > 0 && !gjson.Get(r.bodyString, namePath[:idx]).Exists(), true
+}
+
+// Protobuf reports whether the body is protobuf.
+func (r *GenRequest) Protobuf() bool {
+	return r.bodyCodec == bodyProtobuf
+}
+
+func peekArgs(args *fasthttp.Args, name, style string) []string {
+	a := args.PeekMulti(name)
+	if len(a) == 0 {
+		return nil
+	}
+	return splitArray(bytesSliceToStringSlice(a), style)
+}
+
+// GenDefault sets the value of the default tag to the field pointer,
+// found is false if the default value is empty.
+// NOTE:
+//  It is used by the generated code, do not use it directly.
+func GenDefault(fieldPointer interface{}, defaultVal string) (found bool, err error) {
+	b := defaultJSON(reflect.TypeOf(fieldPointer).Elem(), defaultVal)
+	if b == nil {
+		return false, nil
+	}
+	return true, jsonpkg.Unmarshal(b, fieldPointer)
+}
+
+// GenRequiredError returns the error of the missing required parameter.
+// NOTE:
+//  It is used by the generated code, do not use it directly.
+func GenRequiredError(namePath string) error {
+	return defaultBindErrFactory(namePath, msgRequired)
+}
+
+// GenTypeError returns the error of the parameter which does not match the field type.
+// NOTE:
+//  It is used by the generated code, do not use it directly.
+func GenTypeError(namePath string) error {
+	return defaultBindErrFactory(namePath, msgTypeMismatch)
+}
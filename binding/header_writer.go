@@ -14,18 +14,16 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
-// HeaderWriter class for writing header and cookie
+// HeaderWriter class for writing header and cookie, and reading the status code and body of the response struct
 type HeaderWriter struct {
 	headersBinders map[int32]*headersBinder
 	lock           sync.RWMutex
 	tagNames       TagNames
 	vm             *tagexpr.VM
+	keyring        *Keyring
 }
 
-var defaultHeaderWriter = NewHeaderWriter(&TagNames{
-	SetHeader: "header",
-	SetCookie: "cookie",
-})
+var defaultHeaderWriter = NewHeaderWriter(nil)
 
 // WriteHeader writes header and cookie to headersBinder,
 // according to the 'header' and 'cookie' struct tags.
@@ -33,12 +31,51 @@ func WriteHeader(c *fasthttp.RequestCtx, result interface{}) error {
 	return defaultHeaderWriter.Write(c, result)
 }
 
+// WriteResponse writes header and cookie of the response struct,
+// and returns its status code and body according to the 'status' and 'body' struct tags.
+func WriteResponse(c *fasthttp.RequestCtx, result interface{}) (resp Response, ok bool, err error) {
+	return defaultHeaderWriter.WriteResponse(c, result)
+}
+
+// ReadResponse reads the status code and body of the response struct,
+// and prepares its header and cookie, which are written by Response.WriteHeader.
+func ReadResponse(result interface{}) (resp Response, ok bool, err error) {
+	return defaultHeaderWriter.ReadResponse(result)
+}
+
+// ResponseBodyType returns the type of the 'body' field if the type is a response struct.
+func ResponseBodyType(t reflect.Type) (bodyType reflect.Type, isResponse bool, err error) {
+	return defaultHeaderWriter.BodyType(t)
+}
+
 // TagNames struct tag naming
 type TagNames struct {
 	// SetHeader use 'header' by default when empty
 	SetHeader string
 	// SetCookie use 'cookie' by default when empty
 	SetCookie string
+	// Status use 'status' by default when empty
+	Status string
+	// Body use 'body' by default when empty
+	Body string
+}
+
+// Response the status code and body read from the response struct.
+type Response struct {
+	// StatusCode the value of the 'status' field, 0 if the field is absent or zero
+	StatusCode int
+	// Body the value of the 'body' field, nil if the field is absent or nil
+	Body interface{}
+
+	header *preparedHeader
+}
+
+// WriteHeader writes the header and cookie prepared by ReadResponse to w,
+// they are written only once even if it is called again.
+func (resp Response) WriteHeader(w *fasthttp.RequestCtx) {
+	if resp.header != nil {
+		resp.header.write(w)
+	}
 }
 
 // NewHeaderWriter creates *HeaderWriter object.
@@ -48,6 +85,8 @@ func NewHeaderWriter(tagNames *TagNames) *HeaderWriter {
 	}
 	goutil.InitAndGetString(&tagNames.SetHeader, "header")
 	goutil.InitAndGetString(&tagNames.SetCookie, "cookie")
+	goutil.InitAndGetString(&tagNames.Status, "status")
+	goutil.InitAndGetString(&tagNames.Body, "body")
 	return &HeaderWriter{
 		tagNames:       *tagNames,
 		vm:             tagexpr.New(),
@@ -55,32 +94,98 @@ func NewHeaderWriter(tagNames *TagNames) *HeaderWriter {
 	}
 }
 
+// SetKeyring sets the keyring to sign the 'signed' cookies and encrypt the 'encrypted' cookies,
+// e.g. `cookie:"session,signed"`.
+// NOTE:
+//  Writing the signed or encrypted cookie fails if the keyring is nil or empty.
+func (r *HeaderWriter) SetKeyring(k *Keyring) *HeaderWriter {
+	r.keyring = k
+	return r
+}
+
 // Write writes header and cookie from result to w.
+// NOTE:
+//  Nothing is written if any required header or cookie is missing.
 func (r *HeaderWriter) Write(w *fasthttp.RequestCtx, result interface{}) error {
+	_, _, err := r.write(w, result)
+	return err
+}
+
+// WriteResponse writes header and cookie from the response struct to w, and returns its status code and body.
+// NOTE:
+//  ok is false if result is not a response struct, that is a struct with the 'status' or 'body' field,
+//  and the header and cookie of the struct that is not a response struct are not written.
+func (r *HeaderWriter) WriteResponse(w *fasthttp.RequestCtx, result interface{}) (resp Response, ok bool, err error) {
+	resp, ok, err = r.ReadResponse(result)
+	if ok {
+		resp.WriteHeader(w)
+	}
+	return resp, ok, err
+}
+
+// ReadResponse reads the status code and body of the response struct without writing anything,
+// and prepares its header and cookie, which are written by Response.WriteHeader,
+// e.g. after the body is rendered successfully.
+// NOTE:
+//  ok is false if result is not a response struct, that is a struct with the 'status' or 'body' field;
+//  An error is returned if any required header or cookie is missing.
+func (r *HeaderWriter) ReadResponse(result interface{}) (resp Response, ok bool, err error) {
+	binder, expr, err := r.prepare(result)
+	if err != nil || !binder.isResponse() {
+		return resp, false, err
+	}
+	if resp.header, err = binder.prepareHeader(expr, r.keyring); err != nil {
+		return resp, false, err
+	}
+	resp.StatusCode = getInt(expr, binder.statusFS)
+	if binder.bodyFS != "" {
+		fh, _ := expr.Field(binder.bodyFS)
+		if v := fh.Value(false); v.IsValid() && !isNilValue(v) {
+			resp.Body = v.Interface()
+		}
+	}
+	return resp, true, nil
+}
+
+// BodyType returns the type of the 'body' field if the type is a response struct,
+// bodyType is nil if the response struct has no 'body' field.
+func (r *HeaderWriter) BodyType(t reflect.Type) (bodyType reflect.Type, isResponse bool, err error) {
+	t = goutil.DereferenceType(t)
+	if t.Kind() != reflect.Struct {
+		return nil, false, nil
+	}
+	binder, err := r.getOrPrepareBinder(reflect.New(t).Elem())
+	if err != nil || !binder.isResponse() {
+		return nil, false, err
+	}
+	return binder.bodyType, true, nil
+}
+
+func (r *HeaderWriter) write(w *fasthttp.RequestCtx, result interface{}) (*headersBinder, *tagexpr.TagExpr, error) {
+	binder, expr, err := r.prepare(result)
+	if err != nil || binder == nil {
+		return nil, nil, err
+	}
+	return binder, expr, binder.write(w, expr, r.keyring)
+}
+
+func (r *HeaderWriter) prepare(result interface{}) (*headersBinder, *tagexpr.TagExpr, error) {
 	if result == nil {
-		return nil
+		return nil, nil, nil
 	}
 	v, err := r.structValueOf(result)
 	if err != nil || !v.IsValid() {
-		return nil
+		return nil, nil, nil
 	}
 	binder, err := r.getOrPrepareBinder(v)
 	if binder == nil {
-		return err
+		return nil, nil, err
 	}
 	expr, err := r.vm.Run(result)
 	if err != nil {
-		return err
-	}
-	for _, c := range binder.cookies {
-		if err = c.SetCookie(w, expr); err != nil {
-			return err
-		}
-	}
-	for _, h := range binder.headers {
-		h.AddHeader(w, expr)
+		return nil, nil, err
 	}
-	return nil
+	return binder, expr, nil
 }
 
 func (r *HeaderWriter) getOrPrepareBinder(value reflect.Value) (*headersBinder, error) {
@@ -107,15 +212,19 @@ func (r *HeaderWriter) getOrPrepareBinder(value reflect.Value) (*headersBinder,
 		if err != nil {
 			return false
 		}
-		return true
+		err = binder.setResponseField(field, fs, r.tagNames.Status, r.tagNames.Body)
+		return err == nil
 	})
+	if err != nil {
+		return nil, err
+	}
 	if binder.isEmpty() {
 		binder = nil
 	}
 	r.lock.Lock()
 	r.headersBinders[runtimeTypeID] = binder
 	r.lock.Unlock()
-	return binder, err
+	return binder, nil
 }
 
 func cleanTagValue(tagVal string) string {
@@ -130,9 +239,37 @@ func splitRequired(tagVal string) (string, bool) {
 	return name, name != tagVal
 }
 
+// cookieTag the parsed cookie tag, such as 'session,value,signed,required'.
+type cookieTag struct {
+	name, pos                   string
+	required, signed, encrypted bool
+}
+
+func parseCookieTag(tagVal string) cookieTag {
+	a := strings.Split(cleanTagValue(tagVal), ",")
+	t := cookieTag{name: a[0]}
+	for _, s := range a[1:] {
+		switch strings.ToLower(s) {
+		case tagRequired:
+			t.required = true
+		case tagOptSigned:
+			t.signed = true
+		case tagOptEncrypted:
+			t.encrypted = true
+		default:
+			t.pos = s
+		}
+	}
+	return t
+}
+
 type headersBinder struct {
 	cookies map[string]*cookieBinder // <name,cookie>
 	headers map[string]*headerBinder // <name,header>
+
+	statusFS string
+	bodyFS   string
+	bodyType reflect.Type
 }
 
 func (r *headersBinder) setHeaderBinder(field reflect.StructField, fs, tagName string) error {
@@ -140,7 +277,13 @@ func (r *headersBinder) setHeaderBinder(field reflect.StructField, fs, tagName s
 	if !ok {
 		return nil
 	}
-	if err := checkString(field.Name, field.Type); err != nil {
+	var multi bool
+	if t := goutil.DereferenceType(field.Type); t.Kind() == reflect.Slice {
+		if err := checkString(field.Name, t.Elem()); err != nil {
+			return err
+		}
+		multi = true
+	} else if err := checkString(field.Name, field.Type); err != nil {
 		return err
 	}
 	name, required := splitRequired(cleanTagValue(tagVal))
@@ -151,21 +294,98 @@ func (r *headersBinder) setHeaderBinder(field reflect.StructField, fs, tagName s
 		name:          name,
 		valueFS:       fs,
 		valueRequired: required,
+		multi:         multi,
 	}
 	return nil
 }
 
+// setResponseField sets the field of status code or body.
+func (r *headersBinder) setResponseField(field reflect.StructField, fs, statusTag, bodyTag string) error {
+	if _, ok := field.Tag.Lookup(statusTag); ok {
+		if err := checkInt(field.Name, field.Type); err != nil {
+			return err
+		}
+		r.statusFS = fs
+	}
+	if _, ok := field.Tag.Lookup(bodyTag); ok {
+		if field.PkgPath != "" {
+			return fmt.Errorf("body field %s must be exported", field.Name)
+		}
+		r.bodyFS = fs
+		r.bodyType = field.Type
+	}
+	return nil
+}
+
+// write writes the header and cookie to w,
+// nothing is written if any required value is missing.
+func (r *headersBinder) write(w *fasthttp.RequestCtx, tagExpr *tagexpr.TagExpr, keyring *Keyring) error {
+	h, err := r.prepareHeader(tagExpr, keyring)
+	if err != nil {
+		return err
+	}
+	h.write(w)
+	return nil
+}
+
+// preparedHeader the header and cookie read from the struct, which have not been written yet.
+type preparedHeader struct {
+	cookies []*fasthttp.Cookie
+	values  map[string][]string
+}
+
+// prepareHeader reads the header and cookie,
+// an error is returned if any required value is missing.
+func (r *headersBinder) prepareHeader(tagExpr *tagexpr.TagExpr, keyring *Keyring) (*preparedHeader, error) {
+	cookies := make([]*fasthttp.Cookie, 0, len(r.cookies))
+	for _, c := range r.cookies {
+		ck, err := c.newCookie(tagExpr, keyring)
+		if err != nil {
+			for _, ck := range cookies {
+				fasthttp.ReleaseCookie(ck)
+			}
+			return nil, err
+		}
+		cookies = append(cookies, ck)
+	}
+	values := make(map[string][]string, len(r.headers))
+	for name, h := range r.headers {
+		a, err := h.values(tagExpr)
+		if err != nil {
+			for _, ck := range cookies {
+				fasthttp.ReleaseCookie(ck)
+			}
+			return nil, err
+		}
+		values[name] = a
+	}
+	return &preparedHeader{cookies: cookies, values: values}, nil
+}
+
+// write writes the header and cookie to w, and releases the cookies.
+func (h *preparedHeader) write(w *fasthttp.RequestCtx) {
+	for _, ck := range h.cookies {
+		w.Response.Header.SetCookie(ck)
+		fasthttp.ReleaseCookie(ck)
+	}
+	for name, a := range h.values {
+		for _, v := range a {
+			w.Response.Header.Add(name, v)
+		}
+	}
+	h.cookies, h.values = nil, nil
+}
+
 func (r *headersBinder) setCookieBinder(field reflect.StructField, fs, tagName string) error {
 	tagVal, ok := field.Tag.Lookup(tagName)
 	if !ok {
 		return nil
 	}
-	name, required := splitRequired(cleanTagValue(tagVal))
+	tag := parseCookieTag(tagVal)
+	name, required := tag.name, tag.required
 	if name == "" {
 		name = field.Name
 	}
-	a := strings.SplitN(name, ",", 2)
-	name = a[0]
 	c, ok := r.cookies[name]
 	if !ok {
 		c = &cookieBinder{
@@ -173,11 +393,9 @@ func (r *headersBinder) setCookieBinder(field reflect.StructField, fs, tagName s
 		}
 		r.cookies[name] = c
 	}
-	var pos string
-	if len(a) == 2 {
-		pos = a[1]
-	}
-	switch strings.ToLower(pos) {
+	c.signed = c.signed || tag.signed
+	c.encrypted = c.encrypted || tag.encrypted
+	switch strings.ToLower(tag.pos) {
 	case "path":
 		if err := checkString(field.Name, field.Type); err != nil {
 			return err
@@ -257,29 +475,53 @@ func (h *headersBinder) isEmpty() bool {
 	if h == nil {
 		return true
 	}
-	if len(h.cookies) == 0 && len(h.headers) == 0 {
+	if len(h.cookies) == 0 && len(h.headers) == 0 && !h.isResponse() {
 		return true
 	}
 	return false
 }
 
+// isResponse reports whether the struct has the field of status code or body.
+func (h *headersBinder) isResponse() bool {
+	return h != nil && (h.statusFS != "" || h.bodyFS != "")
+}
+
 type headerBinder struct {
 	name          string
 	valueFS       string
 	valueRequired bool
+	// multi is true if the field is a slice, whose elements are added as the values of the header
+	multi bool
 }
 
 func (h *headerBinder) AddHeader(w *fasthttp.RequestCtx, tagExpr *tagexpr.TagExpr) error {
-	v := getString(tagExpr, h.valueFS)
-	if v == "" && h.valueRequired {
-		return fmt.Errorf("the header %s missing required value", h.name)
+	a, err := h.values(tagExpr)
+	if err != nil {
+		return err
+	}
+	for _, v := range a {
+		w.Response.Header.Add(h.name, v)
 	}
-	w.Response.Header.Add(h.name, v)
 	return nil
 }
 
+func (h *headerBinder) values(tagExpr *tagexpr.TagExpr) ([]string, error) {
+	var a []string
+	if h.multi {
+		a = getStrings(tagExpr, h.valueFS)
+	} else if v := getString(tagExpr, h.valueFS); v != "" || !h.valueRequired {
+		a = []string{v}
+	}
+	if len(a) == 0 && h.valueRequired {
+		return nil, fmt.Errorf("the header %s missing required value", h.name)
+	}
+	return a, nil
+}
+
 type cookieBinder struct {
 	name string
+	// signed or encrypted by the keyring
+	signed, encrypted bool
 
 	valueFS    string
 	pathFS     string
@@ -300,53 +542,73 @@ type cookieBinder struct {
 	sameSiteRequired bool
 }
 
-func (c *cookieBinder) SetCookie(w *fasthttp.RequestCtx, tagExpr *tagexpr.TagExpr) (err error) {
-	ck := fasthttp.AcquireCookie()
+func (c *cookieBinder) SetCookie(w *fasthttp.RequestCtx, tagExpr *tagexpr.TagExpr, keyring *Keyring) error {
+	ck, err := c.newCookie(tagExpr, keyring)
+	if err != nil {
+		return err
+	}
+	w.Response.Header.SetCookie(ck)
+	fasthttp.ReleaseCookie(ck)
+	return nil
+}
+
+// newCookie creates the cookie from the fields,
+// the cookie should be released by fasthttp.ReleaseCookie.
+func (c *cookieBinder) newCookie(tagExpr *tagexpr.TagExpr, keyring *Keyring) (ck *fasthttp.Cookie, err error) {
+	ck = fasthttp.AcquireCookie()
 	defer func() {
 		if err != nil {
 			fasthttp.ReleaseCookie(ck)
-		} else {
-			w.Response.Header.SetCookie(ck)
+			ck = nil
 		}
 	}()
 	c.setName(ck)
-	if err = c.setValue(ck, tagExpr); err != nil {
-		return err
+	if err = c.setValue(ck, tagExpr, keyring); err != nil {
+		return ck, err
 	}
 	if err = c.setPath(ck, tagExpr); err != nil {
-		return err
+		return ck, err
 	}
 	if err = c.setDomain(ck, tagExpr); err != nil {
-		return err
+		return ck, err
 	}
 	if err = c.setExpires(ck, tagExpr); err != nil {
-		return err
+		return ck, err
 	}
 	if err = c.setMaxAge(ck, tagExpr); err != nil {
-		return err
+		return ck, err
 	}
 	if err = c.setSecure(ck, tagExpr); err != nil {
-		return err
+		return ck, err
 	}
 	if err = c.setHttpOnly(ck, tagExpr); err != nil {
-		return err
+		return ck, err
 	}
 	if err = c.setSameSite(ck, tagExpr); err != nil {
-		return err
+		return ck, err
 	}
-	return nil
+	return ck, nil
 }
 
 func (c *cookieBinder) setName(ck *fasthttp.Cookie) {
 	ck.SetKey(c.name)
 }
 
-func (c *cookieBinder) setValue(ck *fasthttp.Cookie, tagExpr *tagexpr.TagExpr) error {
-	v := url.QueryEscape(getString(tagExpr, c.valueFS))
-	if v == "" && c.valueRequired {
-		return fmt.Errorf("the cookie %s missing required Value field", c.name)
+func (c *cookieBinder) setValue(ck *fasthttp.Cookie, tagExpr *tagexpr.TagExpr, keyring *Keyring) (err error) {
+	v := getString(tagExpr, c.valueFS)
+	if v == "" {
+		if c.valueRequired {
+			return fmt.Errorf("the cookie %s missing required Value field", c.name)
+		}
+	} else if c.encrypted {
+		v, err = keyring.Encrypt(c.name, v)
+	} else if c.signed {
+		v, err = keyring.Sign(c.name, v)
+	}
+	if err != nil {
+		return fmt.Errorf("the cookie %s cannot be secured: %s", c.name, err)
 	}
-	ck.SetValue(v)
+	ck.SetValue(url.QueryEscape(v))
 	return nil
 }
 
@@ -452,6 +714,26 @@ func getTime(tagExpr *tagexpr.TagExpr, fs string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("type %s cannot be converted to time.Time", v.Type().String())
 }
 
+func getStrings(tagExpr *tagexpr.TagExpr, fs string) []string {
+	v := getElem(tagExpr, fs)
+	if !v.IsValid() || v.Len() == 0 {
+		return nil
+	}
+	a := make([]string, v.Len())
+	for i := range a {
+		a[i] = goutil.DereferenceValue(v.Index(i)).String()
+	}
+	return a
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
 func getString(tagExpr *tagexpr.TagExpr, fs string) string {
 	v := getElem(tagExpr, fs)
 	if !v.IsValid() {
@@ -0,0 +1,95 @@
+package binding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHeaderWriter(t *testing.T) {
+	type Result struct {
+		ID    string   `header:"X-Id,required"`
+		Tags  []string `header:"X-Tag"`
+		Token string   `cookie:"token"`
+	}
+	w := NewHeaderWriter(nil)
+	ctx := new(fasthttp.RequestCtx)
+	err := w.Write(ctx, &Result{Token: "t"})
+	assert.EqualError(t, err, "the header X-Id missing required value")
+	assert.Empty(t, ctx.Response.Header.PeekCookie("token"))
+
+	err = w.Write(ctx, &Result{ID: "1", Tags: []string{"a", "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", string(ctx.Response.Header.Peek("X-Id")))
+	var tags []string
+	ctx.Response.Header.VisitAll(func(k, v []byte) {
+		if string(k) == "X-Tag" {
+			tags = append(tags, string(v))
+		}
+	})
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	_, ok, err := w.WriteResponse(ctx, &Result{})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	type BadHeader struct {
+		ID int `header:"X-Id"`
+	}
+	for i := 0; i < 2; i++ {
+		err = w.Write(ctx, &BadHeader{})
+		assert.EqualError(t, err, "header field ID must be string or *string, but got: int")
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	type Body struct {
+		Name string `json:"name"`
+	}
+	type Response struct {
+		Status int    `status:""`
+		ID     string `header:"X-Id"`
+		Body   *Body  `body:""`
+	}
+	ctx := new(fasthttp.RequestCtx)
+	resp, ok, err := WriteResponse(ctx, Response{Status: 201, ID: "1", Body: &Body{Name: "a"}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, &Body{Name: "a"}, resp.Body)
+	assert.Equal(t, "1", string(ctx.Response.Header.Peek("X-Id")))
+
+	resp, ok, err = WriteResponse(ctx, &Response{})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Response{}.Status, resp.StatusCode)
+	assert.Nil(t, resp.Body)
+
+	// read the response without writing until WriteHeader is called
+	ctx = new(fasthttp.RequestCtx)
+	resp, ok, err = ReadResponse(&Response{ID: "2", Body: &Body{Name: "b"}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, &Body{Name: "b"}, resp.Body)
+	assert.Empty(t, ctx.Response.Header.Peek("X-Id"))
+	resp.WriteHeader(ctx)
+	resp.WriteHeader(ctx)
+	assert.Equal(t, "2", string(ctx.Response.Header.Peek("X-Id")))
+	var ids int
+	ctx.Response.Header.VisitAll(func(k, _ []byte) {
+		if string(k) == "X-Id" {
+			ids++
+		}
+	})
+	assert.Equal(t, 1, ids)
+
+	bodyType, isResponse, err := ResponseBodyType(reflect.TypeOf(&Response{}))
+	assert.NoError(t, err)
+	assert.True(t, isResponse)
+	assert.Equal(t, reflect.TypeOf(&Body{}), bodyType)
+	_, isResponse, err = ResponseBodyType(reflect.TypeOf(Body{}))
+	assert.NoError(t, err)
+	assert.False(t, isResponse)
+}
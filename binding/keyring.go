@@ -0,0 +1,193 @@
+package binding
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	tagOptSigned    = "signed"
+	tagOptEncrypted = "encrypted"
+)
+
+var errNoKey = errors.New("the keyring has no key")
+
+// Keyring the secret keys of the signed and encrypted cookies,
+// which is safe for concurrent use.
+// NOTE:
+//  The first key signs and encrypts, and all the keys verify and decrypt,
+//  so the key is rotated by Rotate and the old keys are retired by SetKeys;
+//  The signed value is readable by the client, use the encrypted one to hide the value.
+type Keyring struct {
+	lock sync.RWMutex
+	keys []keyPair
+}
+
+// keyPair the keys derived from a secret, so that signing and encryption never share a key.
+type keyPair struct {
+	sign []byte
+	aead cipher.AEAD
+}
+
+// NewKeyring creates the keyring, the first key is the current one.
+func NewKeyring(keys ...[]byte) *Keyring {
+	k := new(Keyring)
+	k.SetKeys(keys...)
+	return k
+}
+
+// SetKeys replaces the keys, the first key is the current one.
+func (k *Keyring) SetKeys(keys ...[]byte) {
+	pairs := make([]keyPair, 0, len(keys))
+	for _, key := range keys {
+		if len(key) > 0 {
+			pairs = append(pairs, newKeyPair(key))
+		}
+	}
+	k.lock.Lock()
+	k.keys = pairs
+	k.lock.Unlock()
+}
+
+// Rotate makes the key current, and keeps the old keys to verify and decrypt the existing cookies.
+func (k *Keyring) Rotate(key []byte) {
+	if len(key) == 0 {
+		return
+	}
+	p := newKeyPair(key)
+	k.lock.Lock()
+	k.keys = append([]keyPair{p}, k.keys...)
+	k.lock.Unlock()
+}
+
+// Len returns the number of the keys.
+func (k *Keyring) Len() int {
+	if k == nil {
+		return 0
+	}
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return len(k.keys)
+}
+
+func newKeyPair(secret []byte) keyPair {
+	block, _ := aes.NewCipher(deriveKey(secret, "encryption"))
+	aead, _ := cipher.NewGCM(block)
+	return keyPair{
+		sign: deriveKey(secret, "signing"),
+		aead: aead,
+	}
+}
+
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("rester cookie " + purpose))
+	return mac.Sum(nil)
+}
+
+func (k *Keyring) pairs() []keyPair {
+	if k == nil {
+		return nil
+	}
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return k.keys
+}
+
+var cookieEncoding = base64.RawURLEncoding
+
+// Sign returns the value with the HMAC-SHA256 signature of the current key,
+// the cookie name is signed too, so that the value cannot be moved to another cookie.
+func (k *Keyring) Sign(name, value string) (string, error) {
+	pairs := k.pairs()
+	if len(pairs) == 0 {
+		return "", errNoKey
+	}
+	return cookieEncoding.EncodeToString([]byte(value)) + "." + cookieEncoding.EncodeToString(signCookie(pairs[0].sign, name, value)), nil
+}
+
+// Verify returns the value of the signed one if its signature matches any key.
+func (k *Keyring) Verify(name, signed string) (value string, ok bool) {
+	i := strings.IndexByte(signed, '.')
+	if i < 0 {
+		return "", false
+	}
+	b, err := cookieEncoding.DecodeString(signed[:i])
+	if err != nil {
+		return "", false
+	}
+	sig, err := cookieEncoding.DecodeString(signed[i+1:])
+	if err != nil {
+		return "", false
+	}
+	value = string(b)
+	for _, p := range k.pairs() {
+		if hmac.Equal(sig, signCookie(p.sign, name, value)) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func signCookie(key []byte, name, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'='})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// Encrypt returns the value encrypted by AES-GCM with the current key,
+// the cookie name is authenticated too, so that the value cannot be moved to another cookie.
+func (k *Keyring) Encrypt(name, value string) (string, error) {
+	pairs := k.pairs()
+	if len(pairs) == 0 {
+		return "", errNoKey
+	}
+	aead := pairs[0].aead
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return cookieEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), []byte(name))), nil
+}
+
+// Decrypt returns the value of the encrypted one if it is decrypted by any key.
+func (k *Keyring) Decrypt(name, encrypted string) (value string, ok bool) {
+	b, err := cookieEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", false
+	}
+	for _, p := range k.pairs() {
+		n := p.aead.NonceSize()
+		if len(b) < n {
+			return "", false
+		}
+		plain, err := p.aead.Open(nil, b[:n], b[n:], []byte(name))
+		if err == nil {
+			return string(plain), true
+		}
+	}
+	return "", false
+}
+
+// openCookie verifies or decrypts the cookie value by the option of the tag,
+// ok is false if the value is tampered.
+func (k *Keyring) openCookie(info *tagInfo, value string) (string, bool) {
+	switch {
+	case info.cookieEncrypted:
+		return k.Decrypt(info.paramName, value)
+	case info.cookieSigned:
+		return k.Verify(info.paramName, value)
+	default:
+		return value, true
+	}
+}
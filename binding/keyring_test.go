@@ -0,0 +1,97 @@
+package binding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestKeyring(t *testing.T) {
+	k := NewKeyring([]byte("old"))
+	signed, err := k.Sign("session", "user=1")
+	assert.NoError(t, err)
+	encrypted, err := k.Encrypt("prefs", "dark")
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, "dark")
+
+	// the old values are still accepted after rotation
+	k.Rotate([]byte("new"))
+	assert.Equal(t, 2, k.Len())
+	v, ok := k.Verify("session", signed)
+	assert.True(t, ok)
+	assert.Equal(t, "user=1", v)
+	v, ok = k.Decrypt("prefs", encrypted)
+	assert.True(t, ok)
+	assert.Equal(t, "dark", v)
+
+	// the value cannot be moved to another cookie
+	_, ok = k.Verify("other", signed)
+	assert.False(t, ok)
+	_, ok = k.Decrypt("other", encrypted)
+	assert.False(t, ok)
+
+	// tampered
+	_, ok = k.Verify("session", strings.Replace(signed, cookieEncoding.EncodeToString([]byte("user=1")), cookieEncoding.EncodeToString([]byte("user=2")), 1))
+	assert.False(t, ok)
+	_, ok = k.Decrypt("prefs", encrypted[:len(encrypted)-2])
+	assert.False(t, ok)
+
+	// the old key is retired
+	k.SetKeys([]byte("new"))
+	_, ok = k.Verify("session", signed)
+	assert.False(t, ok)
+
+	_, err = NewKeyring().Sign("session", "user=1")
+	assert.EqualError(t, err, "the keyring has no key")
+	var nilKeyring *Keyring
+	_, ok = nilKeyring.Verify("session", signed)
+	assert.False(t, ok)
+}
+
+func TestSecuredCookie(t *testing.T) {
+	type Result struct {
+		Session string `cookie:"session,signed"`
+		Prefs   string `cookie:"prefs,value,encrypted"`
+	}
+	type Args struct {
+		Session string `cookie:"session,signed,required"`
+		Prefs   string `cookie:"prefs,encrypted"`
+	}
+	k := NewKeyring([]byte("secret"))
+	w := NewHeaderWriter(nil).SetKeyring(k)
+	b := New(nil).SetKeyring(k)
+
+	resp := new(fasthttp.RequestCtx)
+	assert.NoError(t, w.Write(resp, &Result{Session: "user=1", Prefs: "dark"}))
+	req := new(fasthttp.RequestCtx)
+	resp.Response.Header.VisitAllCookie(func(key, value []byte) {
+		var ck fasthttp.Cookie
+		assert.NoError(t, ck.ParseBytes(value))
+		assert.NotEqual(t, "user=1", string(ck.Value()))
+		req.Request.Header.SetCookieBytesKV(key, ck.Value())
+	})
+	args := new(Args)
+	if assert.NoError(t, b.Bind(args, req)) {
+		assert.Equal(t, "user=1", args.Session)
+		assert.Equal(t, "dark", args.Prefs)
+	}
+
+	// the tampered optional cookie is treated as missing
+	req.Request.Header.SetCookie("prefs", "dark")
+	args = new(Args)
+	if assert.NoError(t, b.Bind(args, req)) {
+		assert.Equal(t, "user=1", args.Session)
+		assert.Equal(t, "", args.Prefs)
+	}
+
+	// the tampered required cookie fails the binding
+	req.Request.Header.SetCookie("session", "user=1")
+	err := b.Bind(new(Args), req)
+	assert.EqualError(t, err, "binding Session: missing required parameter")
+
+	// writing fails without keys
+	err = NewHeaderWriter(nil).Write(resp, &Result{Session: "user=1"})
+	assert.EqualError(t, err, "the cookie session cannot be secured: the keyring has no key")
+}
@@ -0,0 +1,238 @@
+package binding
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/go-tagexpr"
+	"github.com/henrylee2cn/ameda"
+	"github.com/valyala/fasthttp"
+)
+
+// Notation the notation of the nested parameters in query and form
+type Notation uint8
+
+const (
+	// NotationNone the nested parameters are not supported
+	NotationNone Notation = iota
+	// NotationBracket such as 'filter[status]=open' and 'items[0][id]=1',
+	// which is the 'deepObject' style of OpenAPI.
+	NotationBracket
+	// NotationDot such as 'filter.status=open' and 'items[0].id=1',
+	// the index of slice is in brackets.
+	NotationDot
+)
+
+// nestedNode the tree of the nested parameters
+type nestedNode struct {
+	values   []string
+	children map[string]*nestedNode
+}
+
+func (n *nestedNode) child(key string) *nestedNode {
+	if n.children == nil {
+		n.children = make(map[string]*nestedNode)
+	}
+	c := n.children[key]
+	if c == nil {
+		c = new(nestedNode)
+		n.children[key] = c
+	}
+	return c
+}
+
+// splitNestedKey splits the nested part of the key after the parameter name,
+// such as '[a][0]' or '.a[0].b', returns false if the key is not nested.
+func splitNestedKey(s string, notation Notation) ([]string, bool) {
+	var segs []string
+	for len(s) > 0 {
+		switch {
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, false
+			}
+			segs = append(segs, s[1:end])
+			s = s[end+1:]
+		case s[0] == '.' && notation == NotationDot:
+			end := strings.IndexAny(s[1:], ".[")
+			if end < 0 {
+				end = len(s) - 1
+			}
+			if end == 0 {
+				return nil, false
+			}
+			segs = append(segs, s[1:end+1])
+			s = s[end+1:]
+		default:
+			return nil, false
+		}
+	}
+	return segs, len(segs) > 0
+}
+
+// collectNested collects the nested parameters of the name, returns nil if not found.
+func collectNested(values *fasthttp.Args, name string, notation Notation) *nestedNode {
+	var root *nestedNode
+	values.VisitAll(func(k, v []byte) {
+		key := ameda.UnsafeBytesToString(k)
+		if len(key) <= len(name) || key[:len(name)] != name {
+			return
+		}
+		segs, ok := splitNestedKey(key[len(name):], notation)
+		if !ok {
+			return
+		}
+		if root == nil {
+			root = new(nestedNode)
+		}
+		n := root
+		for i, seg := range segs {
+			// 'a[]=1&a[]=2' appends the values to a
+			if seg == "" && i == len(segs)-1 {
+				break
+			}
+			n = n.child(seg)
+		}
+		n.values = append(n.values, string(v))
+	})
+	return root
+}
+
+// isNestedType reports whether the type can be bound from the nested parameters.
+func isNestedType(t reflect.Type, unmarshalers *typeUnmarshalers) bool {
+	t = ameda.DereferenceType(t)
+	if unmarshalers.get(t) != nil || isFormattedType(t, nil, unmarshalers) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	case reflect.Slice:
+		return isNestedType(t.Elem(), unmarshalers)
+	default:
+		return false
+	}
+}
+
+// setNested sets the nested parameters to the value, returns errMismatch if the type does not match.
+// NOTE: c.info is nil
+func setNested(v reflect.Value, n *nestedNode, c unmarshalCtx) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if len(n.children) == 0 {
+		if len(n.values) == 0 {
+			return nil
+		}
+		return setStringsValue(v, n.values, c)
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return setNestedStruct(v, n, c)
+	case reflect.Map:
+		t := v.Type()
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(t, len(n.children)))
+		}
+		for key, child := range n.children {
+			kv := reflect.New(t.Key()).Elem()
+			kc := c
+			kc.looseZeroMode = false
+			if err := setStringsValue(kv, []string{key}, kc); err != nil {
+				return err
+			}
+			ev := reflect.New(t.Elem()).Elem()
+			if err := setNested(ev, child, c); err != nil {
+				return err
+			}
+			v.SetMapIndex(kv, ev)
+		}
+		return nil
+	case reflect.Slice:
+		// the sparse indexes are compacted in order
+		indexes := make([]int, 0, len(n.children))
+		for key := range n.children {
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 {
+				return errMismatch
+			}
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		s := reflect.MakeSlice(v.Type(), len(indexes), len(indexes))
+		for i, idx := range indexes {
+			if err := setNested(s.Index(i), n.children[strconv.Itoa(idx)], c); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	default:
+		return errMismatch
+	}
+}
+
+func setNestedStruct(v reflect.Value, n *nestedNode, c unmarshalCtx) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Anonymous && ameda.DereferenceType(f.Type).Kind() == reflect.Struct {
+			if err := setNested(v.Field(i), n, c); err != nil {
+				return err
+			}
+			continue
+		}
+		child := n.children[nestedFieldName(f)]
+		if child == nil {
+			continue
+		}
+		fc := *c.field
+		fc.Field = f
+		cc := c
+		cc.field = &fc
+		if err := setNested(v.Field(i), child, cc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestedFieldName returns the json tag name of the field, or the field name if not tagged.
+func nestedFieldName(f reflect.StructField) string {
+	name := f.Tag.Get(tagJSON)
+	if i := strings.IndexByte(name, ','); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+// bindNested binds the nested parameters, such as 'filter[status]=open'.
+func (p *paramInfo) bindNested(info *tagInfo, expr *tagexpr.TagExpr, values *fasthttp.Args) (bool, error) {
+	n := collectNested(values, info.paramName, p.notation)
+	if n == nil {
+		return false, nil
+	}
+	v, err := p.getField(expr, true)
+	if err != nil || !v.IsValid() {
+		return false, err
+	}
+	c := p.unmarshalCtx(info)
+	// the options of the field, such as 'layout=', are not applied to the nested values
+	c.info = nil
+	if setNested(v, n, c) != nil {
+		return true, info.typeError
+	}
+	return true, nil
+}
@@ -0,0 +1,64 @@
+package binding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestBindNested(t *testing.T) {
+	type Item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type Recv struct {
+		Filter struct {
+			Status string   `json:"status"`
+			Owner  *string  `json:"owner"`
+			Tags   []string `json:"tags"`
+		} `query:"filter"`
+		Labels map[string]int    `query:"labels"`
+		Items  []*Item           `query:"items"`
+		Form   map[string]string `form:"form"`
+	}
+	var cases = []struct {
+		notation Notation
+		query    string
+		form     string
+	}{
+		{NotationBracket, "filter[status]=open&filter[owner]=me&filter[tags]=a&filter[tags]=b&labels[x]=1&labels[y]=2&items[3][id]=2&items[0][id]=1&items[0][name]=n", "form[k]=v"},
+		{NotationDot, "filter.status=open&filter.owner=me&filter.tags=a&filter.tags=b&labels.x=1&labels.y=2&items[3].id=2&items[0].id=1&items.0.name=n", "form.k=v"},
+	}
+	for _, c := range cases {
+		ctx := new(fasthttp.RequestCtx)
+		ctx.Request.SetRequestURI("/?" + c.query)
+		ctx.Request.Header.SetMethod("POST")
+		ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+		ctx.Request.SetBodyString(c.form)
+		recv := new(Recv)
+		if !assert.NoError(t, New(&Config{Notation: c.notation}).BindAndValidate(recv, ctx)) {
+			continue
+		}
+		assert.Equal(t, "open", recv.Filter.Status)
+		assert.Equal(t, "me", *recv.Filter.Owner)
+		assert.Equal(t, []string{"a", "b"}, recv.Filter.Tags)
+		assert.Equal(t, map[string]int{"x": 1, "y": 2}, recv.Labels)
+		assert.Equal(t, []*Item{{ID: 1, Name: "n"}, {ID: 2}}, recv.Items)
+		assert.Equal(t, map[string]string{"k": "v"}, recv.Form)
+	}
+
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.SetRequestURI("/?labels[x]=a")
+	assert.EqualError(t, New(&Config{Notation: NotationBracket}).BindAndValidate(new(Recv), ctx),
+		"binding Labels: parameter type does not match binding data")
+	recv := new(Recv)
+	assert.NoError(t, New(nil).BindAndValidate(recv, ctx))
+	assert.Nil(t, recv.Labels)
+
+	params, err := New(nil).SetNotation(NotationBracket).Params(reflect.TypeOf(Recv{}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "deepObject", params[0].Style)
+	}
+}
@@ -0,0 +1,91 @@
+package binding
+
+import "reflect"
+
+// Param the description of a request parameter bound to a struct field
+type Param struct {
+	// In the parameter position, such as path, query, header, cookie, form, json, protobuf, xml, raw_body and ctx
+	In string
+	// Name the parameter name, or the name path of the body field, such as 'a.b'
+	Name string
+	// Required is true if the parameter is required
+	Required bool
+	// Implicit is true if the position is inferred from the default binding order
+	Implicit bool
+	// FieldSelector the selector of the struct field, such as 'A.B'
+	FieldSelector string
+	// Field the struct field
+	Field reflect.StructField
+	// Validator the validator expression of the field
+	Validator string
+	// Default the raw value of the default tag
+	Default string
+	// Style the OpenAPI style of the query parameter, such as 'deepObject', 'pipeDelimited',
+	// and 'form' for the comma separated array
+	Style string
+}
+
+// Params returns the descriptions of the request parameters bound to the fields of the struct type.
+// NOTE:
+//  The parameters of a field are sorted in the binding order.
+func (b *Binding) Params(structType reflect.Type) ([]*Param, error) {
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, b.bindErrFactory("", "receiver must be a struct type")
+	}
+	recv, err := b.getOrPrepareReceiver(reflect.New(structType).Elem())
+	if err != nil {
+		return nil, err
+	}
+	var params []*Param
+	for _, p := range recv.params {
+		var defaultVal string
+		for _, info := range p.tagInfos {
+			if info.paramIn == default_val {
+				defaultVal = info.paramName
+			}
+		}
+		validator := p.structField.Tag.Get(b.config.Validator)
+		for _, info := range p.tagInfos {
+			if info.paramIn == default_val {
+				continue
+			}
+			name := info.paramName
+			if info.paramIn == json || info.paramIn == protobuf || info.paramIn.isBodyCodec() {
+				name = info.namePath
+			}
+			params = append(params, &Param{
+				In:            info.paramIn.String(),
+				Name:          name,
+				Required:      info.required,
+				Implicit:      info.implicit,
+				FieldSelector: p.fieldSelector,
+				Field:         p.structField,
+				Validator:     validator,
+				Default:       defaultVal,
+				Style:         p.style(info),
+			})
+		}
+	}
+	return params, nil
+}
+
+func (p *paramInfo) style(info *tagInfo) string {
+	if info.paramIn != query {
+		return ""
+	}
+	if p.nested && p.notation == NotationBracket {
+		return "deepObject"
+	}
+	switch info.arrayStyle {
+	case styleComma:
+		return "form"
+	case stylePipe:
+		return "pipeDelimited"
+	case styleSpace:
+		return "spaceDelimited"
+	}
+	return ""
+}
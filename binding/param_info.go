@@ -27,12 +27,42 @@ type paramInfo struct {
 	bindErrFactory func(failField, msg string) error
 	looseZeroMode  bool
 	defaultVal     []byte
+	fileKind       fileKind
+	// nested is true if the field can be bound from the nested parameters of notation
+	nested       bool
+	notation     Notation
+	unmarshalers *typeUnmarshalers
+	keyring      *Keyring
 }
 
+// unmarshalCtx the context of setting the parameter strings to a value.
+type unmarshalCtx struct {
+	// info is nil if the value is not of a tagged field
+	info          *tagInfo
+	field         *FieldContext
+	unmarshalers  *typeUnmarshalers
+	looseZeroMode bool
+}
+
+func (p *paramInfo) unmarshalCtx(info *tagInfo) unmarshalCtx {
+	return unmarshalCtx{
+		info:          info,
+		field:         info.fieldCtx,
+		unmarshalers:  p.unmarshalers,
+		looseZeroMode: p.looseZeroMode,
+	}
+}
+
+// name returns the name of the field in the body,
+// the tag name of the registered body codec is used for its position and the json tag name is used for others.
 func (p *paramInfo) name(paramIn in) string {
 	var name string
+	nameIn := json
+	if paramIn.isBodyCodec() {
+		nameIn = paramIn
+	}
 	for _, info := range p.tagInfos {
-		if info.paramIn == json {
+		if info.paramIn == nameIn {
 			name = info.paramName
 			break
 		}
@@ -81,6 +111,39 @@ func (p *paramInfo) bindRawBody(info *tagInfo, expr *tagexpr.TagExpr, bodyBytes
 	}
 }
 
+// bindUserValue binds the value set by RequestCtx.SetUserValue,
+// the value is assigned if its type matches, and the string value is converted like query.
+func (p *paramInfo) bindUserValue(info *tagInfo, expr *tagexpr.TagExpr, req *fasthttp.RequestCtx) (bool, error) {
+	r := req.UserValue(info.paramName)
+	if r == nil {
+		if info.required {
+			return false, info.requiredError
+		}
+		return false, nil
+	}
+	v, err := p.getField(expr, false)
+	if err != nil || !v.IsValid() {
+		return false, err
+	}
+	rv := reflect.ValueOf(r)
+	t := v.Type()
+	switch {
+	case rv.Type().AssignableTo(t):
+		v.Set(rv)
+	case t.Kind() == reflect.Ptr && rv.Type().AssignableTo(t.Elem()):
+		pv := reflect.New(t.Elem())
+		pv.Elem().Set(rv)
+		v.Set(pv)
+	case rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Type().Elem().AssignableTo(t):
+		v.Set(rv.Elem())
+	case rv.Kind() == reflect.String:
+		return true, p.bindStringSlice(info, expr, []string{rv.String()})
+	default:
+		return true, info.typeError
+	}
+	return true, nil
+}
+
 func (p *paramInfo) bindPath(info *tagInfo, expr *tagexpr.TagExpr, req *fasthttp.RequestCtx) (bool, error) {
 	r, found := req.UserValue(info.paramName).(string)
 	if !found {
@@ -108,14 +171,18 @@ func (p *paramInfo) bindHeader(info *tagInfo, expr *tagexpr.TagExpr, reqHeader *
 }
 
 func (p *paramInfo) bindCookie(info *tagInfo, expr *tagexpr.TagExpr, reqHeader *fasthttp.RequestHeader) error {
-	r := reqHeader.Cookie(info.paramName)
-	if len(r) == 0 {
+	r := ameda.UnsafeBytesToString(reqHeader.Cookie(info.paramName))
+	if r != "" {
+		// the tampered cookie is treated as missing
+		r, _ = p.keyring.openCookie(info, r)
+	}
+	if r == "" {
 		if info.required {
 			return info.requiredError
 		}
 		return nil
 	}
-	return p.bindStringSlice(info, expr, []string{ameda.UnsafeBytesToString(r)})
+	return p.bindStringSlice(info, expr, []string{r})
 }
 
 func (p *paramInfo) bindOrRequireBody(info *tagInfo, expr *tagexpr.TagExpr, bodyCodec codec, bodyString string, postForm *fasthttp.Args) (bool, error) {
@@ -163,7 +230,33 @@ func (p *paramInfo) checkRequireJSON(info *tagInfo, expr *tagexpr.TagExpr, bodyS
 	return true, nil
 }
 
+// checkRequireBody checks the field of the body decoded by the registered codec.
+func (p *paramInfo) checkRequireBody(info *tagInfo, expr *tagexpr.TagExpr, bc *bodyCodecInfo, bodyBytes []byte) (bool, error) {
+	var requiredError error
+	if info.required {
+		requiredError = info.requiredError
+	}
+	if !bc.codec.Exists(bodyBytes, info.namePath) {
+		idx := strings.LastIndex(info.namePath, ".")
+		// There should be a superior but it is empty, no error is reported
+		if idx > 0 && !bc.codec.Exists(bodyBytes, info.namePath[:idx]) {
+			return true, nil
+		}
+		return false, requiredError
+	}
+	v, err := p.getField(expr, false)
+	if err != nil || !v.IsValid() {
+		return false, requiredError
+	}
+	return true, nil
+}
+
 func (p *paramInfo) bindMapStrings(info *tagInfo, expr *tagexpr.TagExpr, values *fasthttp.Args) (bool, error) {
+	if p.nested {
+		if found, err := p.bindNested(info, expr, values); found || err != nil {
+			return found, err
+		}
+	}
 	r := values.PeekMulti(info.paramName)
 	if len(r) == 0 {
 		if info.required {
@@ -171,7 +264,20 @@ func (p *paramInfo) bindMapStrings(info *tagInfo, expr *tagexpr.TagExpr, values
 		}
 		return false, nil
 	}
-	return true, p.bindStringSlice(info, expr, bytesSliceToStringSlice(r))
+	return true, p.bindStringSlice(info, expr, splitArray(bytesSliceToStringSlice(r), info.arrayStyle))
+}
+
+// splitArray splits the values by the separator of the array style.
+func splitArray(a []string, style string) []string {
+	sep := arrayStyleSeps[style]
+	if sep == "" {
+		return a
+	}
+	r := make([]string, 0, len(a))
+	for _, s := range a {
+		r = append(r, strings.Split(s, sep)...)
+	}
+	return r
 }
 
 func bytesSliceToStringSlice(bs [][]byte) []string {
@@ -188,8 +294,20 @@ func (p *paramInfo) bindStringSlice(info *tagInfo, expr *tagexpr.TagExpr, a []st
 	if err != nil || !v.IsValid() {
 		return err
 	}
+	if setStringsValue(goutil.DereferenceValue(v), a, p.unmarshalCtx(info)) != nil {
+		return info.typeError
+	}
+	return nil
+}
 
-	v = goutil.DereferenceValue(v)
+// setStringsValue sets the strings to the value, returns errMismatch if the type does not match.
+// NOTE: len(a)>0
+func setStringsValue(v reflect.Value, a []string, c unmarshalCtx) error {
+	if handled, err := setFormattedValue(v, a[0], c); handled {
+		return err
+	}
+	looseZeroMode := c.looseZeroMode
+	var err error
 	switch v.Kind() {
 	case reflect.String:
 		v.SetString(a[0])
@@ -198,98 +316,106 @@ func (p *paramInfo) bindStringSlice(info *tagInfo, expr *tagexpr.TagExpr, a []st
 	case reflect.Bool:
 		var bol bool
 		bol, err = strconv.ParseBool(a[0])
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetBool(bol)
 			return nil
 		}
 	case reflect.Float32:
 		var f float64
 		f, err = strconv.ParseFloat(a[0], 32)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetFloat(f)
 			return nil
 		}
 	case reflect.Float64:
 		var f float64
 		f, err = strconv.ParseFloat(a[0], 64)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetFloat(f)
 			return nil
 		}
 	case reflect.Int64, reflect.Int:
 		var i int64
 		i, err = strconv.ParseInt(a[0], 10, 64)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetInt(i)
 			return nil
 		}
 	case reflect.Int32:
 		var i int64
 		i, err = strconv.ParseInt(a[0], 10, 32)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetInt(i)
 			return nil
 		}
 	case reflect.Int16:
 		var i int64
 		i, err = strconv.ParseInt(a[0], 10, 16)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetInt(i)
 			return nil
 		}
 	case reflect.Int8:
 		var i int64
 		i, err = strconv.ParseInt(a[0], 10, 8)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetInt(i)
 			return nil
 		}
 	case reflect.Uint64, reflect.Uint:
 		var u uint64
 		u, err = strconv.ParseUint(a[0], 10, 64)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetUint(u)
 			return nil
 		}
 	case reflect.Uint32:
 		var u uint64
 		u, err = strconv.ParseUint(a[0], 10, 32)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetUint(u)
 			return nil
 		}
 	case reflect.Uint16:
 		var u uint64
 		u, err = strconv.ParseUint(a[0], 10, 16)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetUint(u)
 			return nil
 		}
 	case reflect.Uint8:
 		var u uint64
 		u, err = strconv.ParseUint(a[0], 10, 8)
-		if err == nil || (a[0] == "" && p.looseZeroMode) {
+		if err == nil || (a[0] == "" && looseZeroMode) {
 			v.SetUint(u)
 			return nil
 		}
 	case reflect.Slice:
-		vv, err := stringsToValue(v.Type().Elem(), a, p.looseZeroMode)
+		if elem := v.Type().Elem(); isFormattedType(elem, c.info, c.unmarshalers) {
+			vv, err := formattedSlice(v.Type(), a, c)
+			if err != nil {
+				return err
+			}
+			v.Set(vv)
+			return nil
+		}
+		vv, err := stringsToValue(v.Type().Elem(), a, c)
 		if err == nil {
 			v.Set(vv)
 			return nil
 		}
 		fallthrough
 	default:
-		fn := typeUnmarshalFuncs[v.Type()]
+		fn := c.unmarshalers.get(v.Type())
 		if fn != nil {
-			vv, err := fn(a[0], p.looseZeroMode)
+			vv, err := fn(c.field, a[0], looseZeroMode)
 			if err == nil {
 				v.Set(vv)
 				return nil
 			}
 		}
 	}
-	return info.typeError
+	return errMismatch
 }
 
 func (p *paramInfo) bindDefaultVal(expr *tagexpr.TagExpr, defaultValue []byte) (bool, error) {
@@ -309,28 +435,31 @@ func (p *paramInfo) setDefaultVal() error {
 		if info.paramIn != default_val {
 			continue
 		}
-
-		defaultVal := info.paramName
-		st := ameda.DereferenceType(p.structField.Type)
-		switch st.Kind() {
-		case reflect.String:
-			p.defaultVal, _ = jsonpkg.Marshal(defaultVal)
-			continue
-		case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
-			// escape single quote and double quote, replace single quote with double quote
-			defaultVal = strings.Replace(defaultVal, `"`, `\"`, -1)
-			defaultVal = strings.Replace(defaultVal, `\'`, specialChar, -1)
-			defaultVal = strings.Replace(defaultVal, `'`, `"`, -1)
-			defaultVal = strings.Replace(defaultVal, specialChar, `'`, -1)
-		}
-		p.defaultVal = ameda.UnsafeStringToBytes(defaultVal)
+		p.defaultVal = defaultJSON(p.structField.Type, info.paramName)
 	}
 	return nil
 }
 
+// defaultJSON converts the raw value of the default tag to JSON.
+func defaultJSON(t reflect.Type, defaultVal string) []byte {
+	switch ameda.DereferenceType(t).Kind() {
+	case reflect.String:
+		b, _ := jsonpkg.Marshal(defaultVal)
+		return b
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		// escape single quote and double quote, replace single quote with double quote
+		defaultVal = strings.Replace(defaultVal, `"`, `\"`, -1)
+		defaultVal = strings.Replace(defaultVal, `\'`, specialChar, -1)
+		defaultVal = strings.Replace(defaultVal, `'`, `"`, -1)
+		defaultVal = strings.Replace(defaultVal, specialChar, `'`, -1)
+	}
+	return ameda.UnsafeStringToBytes(defaultVal)
+}
+
 var errMismatch = errors.New("type mismatch")
 
-func stringsToValue(t reflect.Type, a []string, emptyAsZero bool) (reflect.Value, error) {
+func stringsToValue(t reflect.Type, a []string, c unmarshalCtx) (reflect.Value, error) {
+	emptyAsZero := c.looseZeroMode
 	var i interface{}
 	var err error
 	var ptrDepth int
@@ -370,13 +499,13 @@ func stringsToValue(t reflect.Type, a []string, emptyAsZero bool) (reflect.Value
 	case reflect.Uint8:
 		i, err = goutil.StringsToUint8s(a, emptyAsZero)
 	default:
-		fn := typeUnmarshalFuncs[t]
+		fn := c.unmarshalers.get(t)
 		if fn == nil {
 			return reflect.Value{}, errMismatch
 		}
 		v := reflect.New(reflect.SliceOf(t)).Elem()
 		for _, s := range a {
-			vv, err := fn(s, emptyAsZero)
+			vv, err := fn(c.field, s, emptyAsZero)
 			if err != nil {
 				return reflect.Value{}, errMismatch
 			}
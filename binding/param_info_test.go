@@ -0,0 +1,74 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestArrayStyle(t *testing.T) {
+	type Recv struct {
+		A []int    `query:"a,style=comma"`
+		B []string `query:"b,required,style=pipe"`
+		C []string `query:"c,style=space"`
+		D []int    `query:"d,style=multi"`
+		E []string `query:"e"`
+	}
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.SetRequestURI("/?a=1,2,3&a=4&b=x|y&c=x+y&d=1&d=2&e=x,y")
+	recv := new(Recv)
+	if assert.NoError(t, New(nil).BindAndValidate(recv, ctx)) {
+		assert.Equal(t, []int{1, 2, 3, 4}, recv.A)
+		assert.Equal(t, []string{"x", "y"}, recv.B)
+		assert.Equal(t, []string{"x", "y"}, recv.C)
+		assert.Equal(t, []int{1, 2}, recv.D)
+		assert.Equal(t, []string{"x,y"}, recv.E)
+	}
+	ctx.Request.SetRequestURI("/?a=1,x&b=y")
+	assert.EqualError(t, New(nil).BindAndValidate(new(Recv), ctx), "binding A: parameter type does not match binding data")
+
+	params, err := New(nil).Params(reflect.TypeOf(Recv{}))
+	if assert.NoError(t, err) {
+		var styles []string
+		for _, p := range params {
+			styles = append(styles, p.Style)
+		}
+		assert.Equal(t, []string{"form", "pipeDelimited", "spaceDelimited", "", ""}, styles)
+	}
+
+	type BadStyle struct {
+		A []int `query:"a,style=csv"`
+	}
+	assert.EqualError(t, New(nil).BindAndValidate(new(BadStyle), ctx), `binding A: unknown array style "csv", expect comma, pipe, space or multi`)
+	_, err = New(nil).Params(reflect.TypeOf(BadStyle{}))
+	assert.EqualError(t, err, `binding A: unknown array style "csv", expect comma, pipe, space or multi`)
+}
+
+func TestBindUserValue(t *testing.T) {
+	type User struct{ Name string }
+	type Recv struct {
+		User     *User        `ctx:"user,required"`
+		UserElem User         `ctx:"user"`
+		Tenant   string       `ctx:"tenant"`
+		ID       int          `ctx:"id"`
+		Any      fmt.Stringer `ctx:"any"`
+	}
+	ctx := new(fasthttp.RequestCtx)
+	ctx.SetUserValue("user", &User{Name: "henry"})
+	ctx.SetUserValue("tenant", "t1")
+	ctx.SetUserValue("id", "7")
+	recv := new(Recv)
+	if assert.NoError(t, New(nil).BindAndValidate(recv, ctx)) {
+		assert.Equal(t, "henry", recv.User.Name)
+		assert.Equal(t, "henry", recv.UserElem.Name)
+		assert.Equal(t, "t1", recv.Tenant)
+		assert.Equal(t, 7, recv.ID)
+		assert.Nil(t, recv.Any)
+	}
+	ctx.SetUserValue("tenant", 1)
+	assert.EqualError(t, New(nil).BindAndValidate(new(Recv), ctx), "binding Tenant: parameter type does not match binding data")
+	assert.EqualError(t, New(nil).BindAndValidate(new(Recv), new(fasthttp.RequestCtx)), "binding User: missing required parameter")
+}
@@ -2,6 +2,8 @@ package binding
 
 import (
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/bytedance/go-tagexpr"
 	"github.com/valyala/fasthttp"
@@ -19,18 +21,36 @@ const (
 	protobuf
 	json
 	raw_body
+	user_value
 	default_val
 	maxIn
 )
 
+var inNames = [maxIn]string{
+	path:        "path",
+	form:        "form",
+	query:       "query",
+	cookie:      "cookie",
+	header:      "header",
+	protobuf:    "protobuf",
+	json:        "json",
+	raw_body:    "raw_body",
+	user_value:  "ctx",
+	default_val: "default",
+}
+
+// String returns the name of the parameter position.
+func (i in) String() string {
+	if i < maxIn {
+		return inNames[i]
+	}
+	if name, ok := bodyCodecTagName(i); ok {
+		return name
+	}
+	return "undefined"
+}
+
 var (
-	allIn = func() []in {
-		a := []in{}
-		for i := undefined + 1; i < maxIn; i++ {
-			a = append(a, i)
-		}
-		return a
-	}()
 	sortedDefaultIn = func() []in {
 		var a []in
 		for i := undefined + 1; i < raw_body; i++ {
@@ -55,6 +75,11 @@ type receiver struct {
 	params []*paramInfo
 
 	looseZeroMode bool
+	strict        bool
+	notation      Notation
+	bodyCodecs    *bodyCodecs
+	unmarshalers  *typeUnmarshalers
+	keyring       *Keyring
 }
 
 func (r *receiver) assginIn(i in, v bool) {
@@ -67,6 +92,10 @@ func (r *receiver) assginIn(i in, v bool) {
 		r.hasBody = r.hasBody || v
 	case cookie:
 		r.hasCookie = r.hasCookie || v
+	default:
+		if i.isBodyCodec() {
+			r.hasBody = r.hasBody || v
+		}
 	}
 }
 
@@ -91,14 +120,19 @@ func (r *receiver) getOrAddParam(fh *tagexpr.FieldHandler, bindErrFactory func(f
 		omitIns:        make(map[in]bool, maxIn),
 		bindErrFactory: bindErrFactory,
 		looseZeroMode:  r.looseZeroMode,
+		fileKind:       fileKindOf(fh.StructField().Type),
+		notation:       r.notation,
+		unmarshalers:   r.unmarshalers,
+		keyring:        r.keyring,
 	}
+	p.nested = r.notation != NotationNone && isNestedType(p.structField.Type, r.unmarshalers)
 	r.params = append(r.params, p)
 	return p
 }
 
 func (r *receiver) getBodyInfo(req *fasthttp.RequestCtx) (codec, []byte, error) {
 	if r.hasBody {
-		return getBodyInfo(req)
+		return getBodyInfo(req, r.bodyCodecs)
 	}
 	return bodyUnsupport, nil, nil
 }
@@ -110,6 +144,9 @@ func (r *receiver) prebindBody(pointer interface{}, val reflect.Value, bodyCodec
 	case bodyProtobuf:
 		return bindProtobuf(pointer, bodyBytes)
 	default:
+		if bc := r.bodyCodecs.get(in(bodyCodec)); bc != nil {
+			return bc.codec.Unmarshal(bodyBytes, pointer)
+		}
 		return nil
 	}
 }
@@ -122,16 +159,12 @@ func (r *receiver) getQuery(req *fasthttp.RequestCtx) *fasthttp.Args {
 }
 
 func (r *receiver) initParams() {
-	names := make(map[string][maxIn]string, len(r.params))
+	superiors := make(map[string]*paramInfo, len(r.params))
 	for _, p := range r.params {
 		if p.structField.Anonymous {
 			continue
 		}
-		a := [maxIn]string{}
-		for _, paramIn := range allIn {
-			a[paramIn] = p.name(paramIn)
-		}
-		names[p.fieldSelector] = a
+		superiors[p.fieldSelector] = p
 	}
 
 	for _, p := range r.params {
@@ -144,16 +177,27 @@ func (r *receiver) initParams() {
 				} else {
 					fs = tagexpr.JoinFieldSelector(fs, s)
 				}
-				name := names[fs][info.paramIn]
-				if name != "" {
-					info.namePath = name + "."
+				if sp := superiors[fs]; sp != nil {
+					info.namePath = sp.name(info.paramIn) + "."
 				}
 			}
 			info.namePath = info.namePath + p.name(info.paramIn)
-			info.requiredError = p.bindErrFactory(info.namePath, "missing required parameter")
-			info.typeError = p.bindErrFactory(info.namePath, "parameter type does not match binding data")
+			info.fieldCtx = &FieldContext{
+				Field:   p.structField,
+				In:      info.paramIn.String(),
+				Name:    info.paramName,
+				Options: info.options,
+			}
+			info.requiredError = p.bindErrFactory(info.namePath, msgRequired)
+			info.typeError = p.bindErrFactory(info.namePath, msgTypeMismatch)
 			info.cannotError = p.bindErrFactory(info.namePath, "parameter cannot be bound")
 			info.contentTypeError = p.bindErrFactory(info.namePath, "does not support binding to the content type body")
+			if info.fileMaxSize > 0 {
+				info.fileSizeError = p.bindErrFactory(info.namePath, "file size exceeds "+strconv.FormatInt(info.fileMaxSize, 10)+" bytes")
+			}
+			if len(info.fileMIMEs) > 0 {
+				info.fileTypeError = p.bindErrFactory(info.namePath, "file type is not one of "+strings.Join(info.fileMIMEs, ", "))
+			}
 		}
 		p.setDefaultVal()
 	}
@@ -0,0 +1,193 @@
+package binding
+
+import (
+	jsonpkg "encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/henrylee2cn/ameda"
+	"github.com/tidwall/gjson"
+	"github.com/valyala/fasthttp"
+)
+
+// Strict the marker type which enables the strict mode for the struct embedding it,
+// whatever Config.Strict is, e.g.
+//   type Args struct {
+//       binding.Strict
+//       PageSize int `query:"page_size"`
+//   }
+type Strict struct{}
+
+// NonStrict the marker type which disables the strict mode for the struct embedding it,
+// whatever Config.Strict is.
+type NonStrict struct{}
+
+var (
+	strictType    = reflect.TypeOf(Strict{})
+	nonStrictType = reflect.TypeOf(NonStrict{})
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*jsonpkg.Unmarshaler)(nil)).Elem()
+
+func isStrictMarker(t reflect.Type) bool {
+	return t == strictType || t == nonStrictType
+}
+
+// strictOf returns the strict mode of the struct type, which is overridden by the embedded marker.
+func strictOf(t reflect.Type, strict bool) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+		switch f.Type {
+		case strictType:
+			return true
+		case nonStrictType:
+			return false
+		}
+	}
+	return strict
+}
+
+// knownKey reports whether the key of query or form is bound to a field.
+func (r *receiver) knownKey(paramIn in, key string) bool {
+	for _, p := range r.params {
+		for _, info := range p.tagInfos {
+			if info.paramIn != paramIn {
+				continue
+			}
+			if key == info.paramName {
+				return true
+			}
+			if p.nested && len(key) > len(info.paramName) && strings.HasPrefix(key, info.paramName) {
+				if _, ok := splitNestedKey(key[len(info.paramName):], p.notation); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// unknownKeys returns the keys which are not bound to any field, in the order of appearance.
+func (r *receiver) unknownKeys(paramIn in, values *fasthttp.Args) []string {
+	var names []string
+	seen := make(map[string]bool)
+	values.VisitAll(func(k, _ []byte) {
+		key := ameda.UnsafeBytesToString(k)
+		if seen[key] || r.knownKey(paramIn, key) {
+			return
+		}
+		key = string(k)
+		seen[key] = true
+		names = append(names, key)
+	})
+	return names
+}
+
+// unknownParams returns the unknown names of query, form and JSON body in strict mode.
+func (r *receiver) unknownParams(structType reflect.Type, req *fasthttp.RequestCtx, bodyCodec codec, bodyBytes []byte) map[in][]string {
+	unknown := make(map[in][]string, 2)
+	if names := r.unknownKeys(query, req.QueryArgs()); len(names) > 0 {
+		unknown[query] = names
+	}
+	switch bodyCodec {
+	case bodyForm:
+		names := r.unknownKeys(form, getPostForm(req, bodyCodec))
+		if mf, err := req.MultipartForm(); err == nil {
+			for key := range mf.File {
+				if !r.knownKey(form, key) {
+					names = append(names, key)
+				}
+			}
+		}
+		if len(names) > 0 {
+			unknown[form] = names
+		}
+	case bodyJSON:
+		var names []string
+		unknownJSONMembers(structType, gjson.ParseBytes(bodyBytes), "", &names)
+		if len(names) > 0 {
+			unknown[json] = names
+		}
+	}
+	return unknown
+}
+
+// unknownJSONMembers appends the name paths of the object members which do not match any field of the type,
+// the members are matched like encoding/json, case-insensitively.
+func unknownJSONMembers(t reflect.Type, v gjson.Result, prefix string, names *[]string) {
+	t = ameda.DereferenceType(t)
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if !v.IsObject() {
+			return
+		}
+		fields := jsonFields(t, nil)
+		v.ForEach(func(key, value gjson.Result) bool {
+			ft, ok := fields[strings.ToLower(key.String())]
+			if !ok {
+				*names = append(*names, prefix+key.String())
+				return true
+			}
+			unknownJSONMembers(ft, value, prefix+key.String()+".", names)
+			return true
+		})
+	case reflect.Slice, reflect.Array:
+		if !v.IsArray() {
+			return
+		}
+		for i, e := range v.Array() {
+			unknownJSONMembers(t.Elem(), e, prefix+strconv.Itoa(i)+".", names)
+		}
+	case reflect.Map:
+		if !v.IsObject() {
+			return
+		}
+		v.ForEach(func(key, value gjson.Result) bool {
+			unknownJSONMembers(t.Elem(), value, prefix+key.String()+".", names)
+			return true
+		})
+	}
+}
+
+// jsonFields returns the types of the fields by the lower case JSON names,
+// including the fields promoted from the embedded structs.
+func jsonFields(t reflect.Type, fields map[string]reflect.Type) map[string]reflect.Type {
+	if fields == nil {
+		fields = make(map[string]reflect.Type, t.NumField())
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(tagJSON)
+		if tag == "-" {
+			continue
+		}
+		name := tag
+		if i := strings.IndexByte(name, ','); i >= 0 {
+			name = name[:i]
+		}
+		if f.Anonymous && name == "" {
+			if ft := ameda.DereferenceType(f.Type); ft.Kind() == reflect.Struct {
+				jsonFields(ft, fields)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		name = strings.ToLower(name)
+		if _, ok := fields[name]; !ok {
+			fields[name] = f.Type
+		}
+	}
+	return fields
+}
@@ -0,0 +1,61 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestStrict(t *testing.T) {
+	type Item struct {
+		ID int `json:"id"`
+	}
+	type Recv struct {
+		PageSize int               `query:"page_size"`
+		Filter   map[string]string `query:"filter"`
+		Name     string            `json:"name"`
+		Items    []Item            `json:"items"`
+	}
+	newCtx := func(uri, body string) *fasthttp.RequestCtx {
+		ctx := new(fasthttp.RequestCtx)
+		ctx.Request.SetRequestURI(uri)
+		ctx.Request.Header.SetMethod("POST")
+		ctx.Request.Header.SetContentType("application/json")
+		ctx.Request.SetBodyString(body)
+		return ctx
+	}
+
+	b := New(nil).SetNotation(NotationBracket).SetStrict(true)
+	err := b.Bind(new(Recv), newCtx("/?page_size=1&filter[a]=x", `{"NAME":"a","items":[{"id":1}]}`))
+	assert.NoError(t, err)
+	err = b.Bind(new(Recv), newCtx("/?page_sise=1&page_size=2&x=1&x=2", `{}`))
+	assert.EqualError(t, err, "binding page_sise, x: unknown query parameter")
+	err = b.Bind(new(Recv), newCtx("/", `{"name":"a","age":1,"items":[{"id":1,"idx":2}]}`))
+	assert.EqualError(t, err, "binding age, items.0.idx: unknown json parameter")
+
+	b = New(&Config{Strict: true, CollectAllErrors: true})
+	err = b.Bind(new(Recv), newCtx("/?x=1", `{"age":1}`))
+	errs, ok := err.(Errors)
+	if assert.True(t, ok, err) && assert.Len(t, errs, 2) {
+		assert.Equal(t, &FieldError{Field: "x", In: "query", Reason: ReasonUnknownParameter, Err: errs[0].Err}, errs[0])
+		assert.Equal(t, &FieldError{Field: "age", In: "json", Reason: ReasonUnknownParameter, Err: errs[1].Err}, errs[1])
+	}
+
+	type NonStrictRecv struct {
+		NonStrict
+		PageSize int `query:"page_size"`
+	}
+	assert.NoError(t, b.Bind(new(NonStrictRecv), newCtx("/?x=1", `{"age":1}`)))
+
+	type StrictRecv struct {
+		Strict
+		A string `form:"a"`
+	}
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+	ctx.Request.SetBodyString("a=1&b=2")
+	err = New(nil).Bind(new(StrictRecv), ctx)
+	assert.EqualError(t, err, "binding b: unknown form parameter")
+}
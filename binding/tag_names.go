@@ -1,9 +1,11 @@
 package binding
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/henrylee2cn/goutil"
 )
@@ -16,19 +18,46 @@ const (
 	defaultTagHeader    = "header"
 	defaultTagCookie    = "cookie"
 	defaultTagRawbody   = "raw_body"
+	defaultTagUserValue = "ctx"
 	defaultTagForm      = "form"
 	defaultTagValidator = "vd"
 	tagProtobuf         = "protobuf"
 	tagJSON             = "json"
+	tagXML              = "xml"
 	tagDefault          = "default"
+	tagOptStyle         = "style="
 )
 
+// the encodings of the array parameter in query and form
+const (
+	styleComma = "comma" // ?ids=1,2,3
+	stylePipe  = "pipe"  // ?ids=1|2|3
+	styleSpace = "space" // ?ids=1%202%203
+	styleMulti = "multi" // ?ids=1&ids=2&ids=3, the default
+)
+
+var arrayStyleSeps = map[string]string{
+	styleComma: ",",
+	stylePipe:  "|",
+	styleSpace: " ",
+}
+
 // Config the struct tag naming and so on
 type Config struct {
 	// LooseZeroMode if set to true,
 	// the empty string request parameter is bound to the zero value of parameter.
 	// NOTE: Suitable for these parameter types: query/header/cookie/form .
 	LooseZeroMode bool
+	// CollectAllErrors if set to true,
+	// binding and validating go on after a failure, and return Errors of all the failed fields.
+	CollectAllErrors bool
+	// Strict if set to true,
+	// the unknown keys of query and form and the unknown members of JSON body are reported as binding errors.
+	// NOTE: The struct embedding Strict or NonStrict overrides it.
+	Strict bool
+	// Notation the notation of the nested parameters in query and form,
+	// the nested struct, map and slice fields are not bound from them if it is NotationNone.
+	Notation Notation
 	// PathParam use 'path' by default when empty
 	PathParam string
 	// Query use 'query' by default when empty
@@ -41,6 +70,8 @@ type Config struct {
 	RawBody string
 	// FormBody use 'form' by default when empty
 	FormBody string
+	// UserValue use 'ctx' by default when empty
+	UserValue string
 	// Validator use 'vd' by default when empty
 	Validator string
 	// protobufBody use 'protobuf' by default when empty
@@ -61,6 +92,7 @@ func (t *Config) init() {
 		goutil.InitAndGetString(&t.Cookie, defaultTagCookie),
 		goutil.InitAndGetString(&t.RawBody, defaultTagRawbody),
 		goutil.InitAndGetString(&t.FormBody, defaultTagForm),
+		goutil.InitAndGetString(&t.UserValue, defaultTagUserValue),
 		goutil.InitAndGetString(&t.Validator, defaultTagValidator),
 		goutil.InitAndGetString(&t.protobufBody, tagProtobuf),
 		goutil.InitAndGetString(&t.jsonBody, tagJSON),
@@ -111,8 +143,32 @@ type tagInfo struct {
 	paramName string
 	required  bool
 	namePath  string
+	// implicit is true if the position is inferred from the default binding order
+	implicit bool
+
+	// fileMaxSize the max size of the uploaded file, set by the option 'maxsize=2MB'
+	fileMaxSize int64
+	// fileMIMEs the allowed MIME types of the uploaded file, set by the option 'mime=image/png|image/*'
+	fileMIMEs []string
+	// arrayStyle the encoding of the array parameter, set by the option 'style=comma'
+	arrayStyle string
+	// timeLayout the layout of the time, set by the option 'layout=2006-01-02' or 'layout=DateOnly'
+	timeLayout string
+	// timeUnix the unit of the unix time, set by the option 'unix' or 'unixmilli'
+	timeUnix time.Duration
+	// cookieSigned the cookie is signed by the keyring, set by the option 'signed'
+	cookieSigned bool
+	// cookieEncrypted the cookie is encrypted by the keyring, set by the option 'encrypted'
+	cookieEncrypted bool
+	// options the raw options following the parameter name
+	options []string
+	// optionError the message of the invalid option, reported when the receiver is prepared
+	optionError string
+	// fieldCtx the context passed to the type unmarshalor function
+	fieldCtx *FieldContext
 
 	requiredError, typeError, cannotError, contentTypeError error
+	fileSizeError, fileTypeError                            error
 }
 
 func (t *tagKV) defaultSplit() *tagInfo {
@@ -126,8 +182,42 @@ func defaultSplitTag(value string) *tagInfo {
 		if i == 0 {
 			info.paramName = v
 		} else {
-			if v == tagRequired || v == tagRequired2 {
+			if v != "" {
+				info.options = append(info.options, v)
+			}
+			switch {
+			case v == tagRequired || v == tagRequired2:
 				info.required = true
+			case strings.HasPrefix(v, tagOptMaxSize):
+				var ok bool
+				if info.fileMaxSize, ok = parseSize(v[len(tagOptMaxSize):]); !ok {
+					info.optionError = fmt.Sprintf("invalid file size %q, expect the form such as 1024, 512KB, 2MB or 1GB", v[len(tagOptMaxSize):])
+				}
+			case strings.HasPrefix(v, tagOptStyle):
+				if style := v[len(tagOptStyle):]; arrayStyleSeps[style] != "" || style == styleMulti {
+					info.arrayStyle = style
+				} else {
+					info.optionError = fmt.Sprintf("unknown array style %q, expect comma, pipe, space or multi", style)
+				}
+			case strings.HasPrefix(v, tagOptLayout):
+				var ok bool
+				if info.timeLayout, ok = parseLayout(v[len(tagOptLayout):]); !ok {
+					info.optionError = fmt.Sprintf("unknown time layout %q", v[len(tagOptLayout):])
+				}
+			case v == tagOptUnix:
+				info.timeUnix = time.Second
+			case v == tagOptUnixMilli:
+				info.timeUnix = time.Millisecond
+			case v == tagOptSigned:
+				info.cookieSigned = true
+			case v == tagOptEncrypted:
+				info.cookieEncrypted = true
+			case strings.HasPrefix(v, tagOptMIME):
+				for _, m := range strings.Split(v[len(tagOptMIME):], "|") {
+					if m != "" {
+						info.fileMIMEs = append(info.fileMIMEs, strings.ToLower(m))
+					}
+				}
 			}
 		}
 	}
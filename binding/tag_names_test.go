@@ -20,7 +20,7 @@ func TestDefaultSplitTag(t *testing.T) {
 		{
 			desc:     "default",
 			input:    "a,required",
-			expected: &tagInfo{paramName: "a", required: true},
+			expected: &tagInfo{paramName: "a", required: true, options: []string{"required"}},
 		},
 	}
 
@@ -44,6 +44,25 @@ func (b *Base) newArg(recvType reflect.Type, idx int, in reflect.Type) (reflect.
 	return b.args.Arg(recvType, idx, in)
 }
 
+// handleOut aborts with the returned error, or hands the returned result to args.
+func (b *Base) handleOut(recvType reflect.Type, out []reflect.Value) {
+	n := len(out)
+	if n == 0 {
+		return
+	}
+	if err, _ := out[n-1].Interface().(error); err != nil {
+		b.Abort(err)
+		return
+	}
+	if n == 2 {
+		if h, ok := b.args.(ResultHandler); ok {
+			if err := h.HandleResult(recvType, out[0]); err != nil {
+				b.Abort(err)
+			}
+		}
+	}
+}
+
 func (b *Base) exec() error {
 	b.index = -1
 	b.Next()
@@ -53,10 +72,41 @@ func (b *Base) exec() error {
 // Next executes the pending methods in the chain inside the calling method.
 func (b *Base) Next() {
 	b.index++
-	for b.index < int8(len(b.ctl.methods)) {
+	n := int8(len(b.ctl.methods))
+	for b.index < n {
+		if b.index == n-1 {
+			b.guard()
+			if b.IsAborted() {
+				return
+			}
+		}
 		b.ctl.methods[b.index](b, b.ctl.recvTypes[b.index], b.recvs[b.index])
 		b.index++
 	}
+	if b.index == n {
+		b.index++
+		b.execJoined()
+	}
+}
+
+// guard calls Guard of args before the last method.
+func (b *Base) guard() {
+	if g, ok := b.args.(Guard); ok {
+		if err := g.Guard(b.recvs[b.index]); err != nil {
+			b.Abort(err)
+		}
+	}
+}
+
+// execJoined executes the following chain joined after the current chain.
+func (b *Base) execJoined() {
+	j, ok := b.args.(*joinArgs)
+	if !ok {
+		return
+	}
+	if err := j.next(j.Args); err != nil {
+		b.Abort(err)
+	}
 }
 
 // Abort prevents pending methods from being called.
@@ -46,6 +46,17 @@ type (
 		Init(NestedStruct) error
 		Arg(recvType reflect.Type, idx int, in reflect.Type) (reflect.Value, error)
 	}
+	// ResultHandler optional interface of Args, handles the non-error result of the method
+	ResultHandler interface {
+		HandleResult(recvType reflect.Type, result reflect.Value) error
+	}
+	// Guard optional interface of Args, checks the receiver before the last method of the chain is called,
+	// the method is not called if Guard returns an error or aborts the chain.
+	// NOTE:
+	//  It is not called for the chains joined before the last one.
+	Guard interface {
+		Guard(recv reflect.Value) error
+	}
 	// Func function to execute method chain
 	Func         func(Args) error
 	methodFunc   func(*Base, reflect.Type, reflect.Value)
@@ -63,6 +74,10 @@ type (
 		curOffset   uintptr
 		curTypeElem reflect.Type
 	}
+	joinArgs struct {
+		Args
+		next Func
+	}
 )
 
 // FindName finds the first method encountered that matches the methodName
@@ -80,9 +95,12 @@ func FindName(methodName string) FindFunc {
 // ErrEmpty no method error
 var ErrEmpty = errors.New("no method chain found")
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // New creates a chained execution function.
 // NOTE:
-//  The method of specifying the name cannot have a return value
+//  The method of specifying the name can only return nothing, (error) or (result, error);
+//  A non-nil error aborts the chain, and a result is passed to Args if it implements ResultHandler
 func New(obj NestedStruct, find FindFunc) (Func, error) {
 	ctl := chainFactory{
 		recv: ameda.DereferenceImplementType(reflect.ValueOf(obj)),
@@ -100,7 +118,8 @@ func New(obj NestedStruct, find FindFunc) (Func, error) {
 
 // Make creates a chained execution function from NestedStruct factory.
 // NOTE:
-//  The method of specifying the name cannot have a return value
+//  The method of specifying the name can only return nothing, (error) or (result, error);
+//  A non-nil error aborts the chain, and a result is passed to Args if it implements ResultHandler
 func Make(factory FactoryFunc, find FindFunc) (Func, error) {
 	obj := factory()
 	t := ameda.DereferenceInterfaceValue(reflect.ValueOf(obj)).Type()
@@ -126,6 +145,31 @@ func Make(factory FactoryFunc, find FindFunc) (Func, error) {
 	return ctl.newChainFunc(), nil
 }
 
+// Join creates a function that executes the chained functions in order.
+// NOTE:
+//  When the methods of a chain are all done, the following chain is executed,
+//  so calling Next() in an outer chain also runs the following chains.
+func Join(fns ...Func) Func {
+	switch len(fns) {
+	case 0:
+		return func(Args) error { return ErrEmpty }
+	case 1:
+		return fns[0]
+	}
+	head, next := fns[0], Join(fns[1:]...)
+	return func(args Args) error {
+		return head(&joinArgs{Args: args, next: next})
+	}
+}
+
+// HandleResult implements ResultHandler.
+func (j *joinArgs) HandleResult(recvType reflect.Type, result reflect.Value) error {
+	if h, ok := j.Args.(ResultHandler); ok {
+		return h.HandleResult(recvType, result)
+	}
+	return nil
+}
+
 func (c *chainFactory) checkMethodName(methodName string) error {
 	if !goutil.IsExportedName(methodName) {
 		return fmt.Errorf("disallow unexported method name %q", methodName)
@@ -190,7 +234,7 @@ func (c *chainFactory) makeMethods(level int, curOffset uintptr, curRecvElem ref
 					return
 				}
 			}
-			fn.Call(inValues)
+			base.handleOut(recvType, fn.Call(inValues))
 		})
 	}
 	for i := curRecvElem.NumField() - 1; i >= 0; i-- {
@@ -222,12 +266,12 @@ func (c *chainFactory) newRecvs() (NestedStruct, []reflect.Value) {
 		topRecvObj = c.factory()
 		topRecvValue = ameda.ReferenceValue(ameda.DereferenceInterfaceValue(reflect.ValueOf(topRecvObj)), c.recvPtrDiff)
 	}
-	lastPtr := topRecvValue.Pointer()
+	lastPtr := unsafe.Pointer(topRecvValue.Pointer())
 	n := len(c.recvInfos)
 	recvs := make([]reflect.Value, n)
 	for i, info := range c.recvInfos {
-		v := reflect.NewAt(info.curTypeElem, unsafe.Pointer(lastPtr+info.curOffset))
-		lastPtr = v.Pointer()
+		v := reflect.NewAt(info.curTypeElem, unsafe.Pointer(uintptr(lastPtr)+info.curOffset))
+		lastPtr = unsafe.Pointer(v.Pointer())
 		recvs[n-1-i] = v // reverse
 	}
 	return topRecvObj, recvs
@@ -246,8 +290,11 @@ func (c *chainFactory) checkMethods(level int, methods []reflect.Method) (*refle
 	if err != nil {
 		return nil, err
 	}
-	if m.Type.NumOut() > 0 {
-		return nil, fmt.Errorf("%s.%s has out parameters", m.Type.In(0).String(), m.Name)
+	switch numOut := m.Type.NumOut(); {
+	case numOut == 0:
+	case numOut <= 2 && m.Type.Out(numOut-1) == errorType:
+	default:
+		return nil, fmt.Errorf("%s.%s has unsupported out parameters, expect none, (error) or (result, error)", m.Type.In(0).String(), m.Name)
 	}
 	return m, nil
 }
@@ -62,6 +62,17 @@ func (t *T2) M6(test *testing.T) error {
 	return nil
 }
 
+func (t *T2) M7(args string) (string, error) {
+	if args == "" {
+		return "", errors.New("T2.M7 test error")
+	}
+	return "T2.M7 result", nil
+}
+
+func (t *T2) M8() int {
+	return 0
+}
+
 type T3 struct {
 	_ struct{}
 	T2
@@ -77,7 +88,21 @@ func (t *T3) M6(test *testing.T, args string) {
 }
 
 type Context struct {
-	t *testing.T
+	t      *testing.T
+	result interface{}
+}
+
+type EmptyContext struct {
+	Context
+}
+
+func (c *EmptyContext) Arg(recvType reflect.Type, idx int, in reflect.Type) (reflect.Value, error) {
+	return reflect.ValueOf(""), nil
+}
+
+func (c *Context) HandleResult(recvType reflect.Type, result reflect.Value) error {
+	c.result = result.Interface()
+	return nil
 }
 
 func (c *Context) Init(recv NestedStruct) error {
@@ -96,7 +121,7 @@ func (c *Context) Arg(recvType reflect.Type, idx int, in reflect.Type) (reflect.
 }
 
 func TestNew(t *testing.T) {
-	ctx := &Context{t}
+	ctx := &Context{t: t}
 	//
 	for _, obj := range []NestedStruct{new(T1), new(T2)} {
 		fn, err := New(obj, FindName("M1"))
@@ -137,11 +162,15 @@ func TestNew(t *testing.T) {
 	err = fn(ctx)
 	assert.NoError(t, err)
 
-	_, err = New(new(T2), FindName("M6"))
-	assert.EqualError(t, err, "*chain.T2.M6 has out parameters")
+	fn, err = New(new(T2), FindName("M6"))
+	assert.NoError(t, err)
+	err = fn(ctx)
+	assert.NoError(t, err)
 
-	_, err = New(new(T3), FindName("M6"))
-	assert.EqualError(t, err, "*chain.T2.M6 has out parameters")
+	fn, err = New(new(T3), FindName("M6"))
+	assert.NoError(t, err)
+	err = fn(ctx)
+	assert.NoError(t, err)
 
 	fn, err = New(new(T3), FindName("M3"))
 	assert.NoError(t, err)
@@ -150,7 +179,7 @@ func TestNew(t *testing.T) {
 }
 
 func TestNewFrom(t *testing.T) {
-	ctx := &Context{t}
+	ctx := &Context{t: t}
 	//
 	for _, factory := range []FactoryFunc{
 		func() NestedStruct { return new(T1) },
@@ -194,14 +223,104 @@ func TestNewFrom(t *testing.T) {
 	err = fn(ctx)
 	assert.NoError(t, err)
 
-	_, err = Make(func() NestedStruct { return new(T2) }, FindName("M6"))
-	assert.EqualError(t, err, "*chain.T2.M6 has out parameters")
+	fn, err = Make(func() NestedStruct { return new(T2) }, FindName("M6"))
+	assert.NoError(t, err)
+	err = fn(ctx)
+	assert.NoError(t, err)
 
-	_, err = Make(func() NestedStruct { return new(T3) }, FindName("M6"))
-	assert.EqualError(t, err, "*chain.T2.M6 has out parameters")
+	fn, err = Make(func() NestedStruct { return new(T3) }, FindName("M6"))
+	assert.NoError(t, err)
+	err = fn(ctx)
+	assert.NoError(t, err)
 
 	fn, err = Make(func() NestedStruct { return new(T3) }, FindName("M3"))
 	assert.NoError(t, err)
 	err = fn(ctx)
 	assert.NoError(t, err)
 }
+
+func TestResult(t *testing.T) {
+	ctx := &Context{t: t}
+	fn, err := New(new(T2), FindName("M7"))
+	assert.NoError(t, err)
+	err = fn(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "T2.M7 result", ctx.result)
+
+	err = fn(&EmptyContext{Context{t: t}})
+	assert.EqualError(t, err, "T2.M7 test error")
+
+	_, err = New(new(T2), FindName("M8"))
+	assert.EqualError(t, err, "*chain.T2.M8 has unsupported out parameters, expect none, (error) or (result, error)")
+}
+
+type T4 struct {
+	Base
+	calls *[]string
+}
+
+func (t *T4) M1(test *testing.T) {
+	*t.calls = append(*t.calls, "T4.M1 start")
+	t.Next()
+	*t.calls = append(*t.calls, "T4.M1 end")
+}
+
+type T5 struct {
+	T4
+}
+
+func (t *T5) M1(test *testing.T) {
+	*t.calls = append(*t.calls, "T5.M1")
+}
+
+func TestJoin(t *testing.T) {
+	ctx := &Context{t: t}
+	var calls []string
+	outer, err := Make(func() NestedStruct { return &T4{calls: &calls} }, FindName("M1"))
+	assert.NoError(t, err)
+	inner, err := Make(func() NestedStruct { return &T5{T4{calls: &calls}} }, FindName("M1"))
+	assert.NoError(t, err)
+	err = Join(outer, inner)(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"T4.M1 start", "T4.M1 start", "T5.M1", "T4.M1 end", "T4.M1 end"}, calls)
+
+	abort, err := New(new(T1), FindName("M4"))
+	assert.NoError(t, err)
+	calls = calls[:0]
+	err = Join(outer, abort)(ctx)
+	assert.EqualError(t, err, "T1.M4 test abort")
+	assert.Equal(t, []string{"T4.M1 start", "T4.M1 end"}, calls)
+}
+
+type GuardContext struct {
+	Context
+	denied bool
+	recvs  []string
+}
+
+func (c *GuardContext) Guard(recv reflect.Value) error {
+	c.recvs = append(c.recvs, recv.Type().String())
+	if c.denied {
+		return errors.New("denied")
+	}
+	return nil
+}
+
+func TestGuard(t *testing.T) {
+	var calls []string
+	outer, err := Make(func() NestedStruct { return &T4{calls: &calls} }, FindName("M1"))
+	assert.NoError(t, err)
+	inner, err := Make(func() NestedStruct { return &T5{T4{calls: &calls}} }, FindName("M1"))
+	assert.NoError(t, err)
+	fn := Join(outer, inner)
+
+	ctx := &GuardContext{Context: Context{t: t}}
+	assert.NoError(t, fn(ctx))
+	assert.Equal(t, []string{"*chain.T5"}, ctx.recvs)
+	assert.Equal(t, []string{"T4.M1 start", "T4.M1 start", "T5.M1", "T4.M1 end", "T4.M1 end"}, calls)
+
+	calls = calls[:0]
+	ctx = &GuardContext{Context: Context{t: t}, denied: true}
+	assert.EqualError(t, fn(ctx), "denied")
+	assert.Equal(t, []string{"T4.M1 start", "T4.M1 start", "T4.M1 end", "T4.M1 end"}, calls)
+}
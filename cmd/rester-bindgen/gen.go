@@ -0,0 +1,499 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const bindingPath = "github.com/henrylee2cn/rester/binding"
+
+// the parameter positions in the binding order, the same as the reflective binding
+const (
+	inPath     = "path"
+	inForm     = "form"
+	inQuery    = "query"
+	inCookie   = "cookie"
+	inHeader   = "header"
+	inProtobuf = "protobuf"
+	inJSON     = "json"
+	inXML      = "xml"
+	inRawBody  = "raw_body"
+	inCtx      = "ctx"
+	inDefault  = "default"
+	tagVd      = "vd"
+)
+
+// the tag names in the parsing order of binding.Config
+var tagNames = []string{inPath, inQuery, inHeader, inCookie, inRawBody, inForm, inCtx, tagVd, inProtobuf, inJSON, inDefault, inXML}
+
+var sortedIns = []string{inPath, inForm, inQuery, inCookie, inHeader, inProtobuf, inJSON, inXML, inRawBody, inCtx, inDefault}
+
+var defaultIns = []string{inPath, inForm, inQuery, inCookie, inHeader, inProtobuf, inJSON}
+
+type tagInfo struct {
+	in       string
+	name     string
+	required bool
+	style    string
+	// secured the option 'signed' or 'encrypted' of the cookie, which needs the keyring of the binding
+	secured string
+}
+
+type field struct {
+	name     string
+	typ      types.Type
+	namePath string
+	infos    []*tagInfo
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string // {path:name}
+	buf     bytes.Buffer
+}
+
+func newGenerator(pkg *types.Package) *generator {
+	return &generator{
+		pkg: pkg,
+		imports: map[string]string{
+			"github.com/valyala/fasthttp": "fasthttp",
+			bindingPath:                   "binding",
+		},
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+	g.imports[pkg.Path()] = pkg.Name()
+	return pkg.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// source returns the formatted source of the generated file.
+func (g *generator) source() ([]byte, error) {
+	var head bytes.Buffer
+	fmt.Fprintf(&head, "// Code generated by rester-bindgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&head, "package %s\n\nimport (\n", g.pkg.Name())
+	// the standard packages are grouped before the others
+	var std, others []string
+	for path := range g.imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			others = append(others, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+	for _, path := range std {
+		fmt.Fprintf(&head, "\t%q\n", path)
+	}
+	if len(std) > 0 && len(others) > 0 {
+		fmt.Fprintf(&head, "\n")
+	}
+	for _, path := range others {
+		fmt.Fprintf(&head, "\t%q\n", path)
+	}
+	fmt.Fprintf(&head, ")\n")
+	src := append(head.Bytes(), g.buf.Bytes()...)
+	formatted, err := format.Source(src)
+	if err != nil {
+		return src, fmt.Errorf("formatting the generated source: %s", err)
+	}
+	return formatted, nil
+}
+
+// generate generates the BindRester method of the struct type,
+// returns error if the struct has the fields which cannot be bound without reflection.
+func (g *generator) generate(named *types.Named) error {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return fmt.Errorf("%s is not a struct type", named.Obj().Name())
+	}
+	var fields []*field
+	for i := 0; i < st.NumFields(); i++ {
+		f, err := parseField(st.Field(i), st.Tag(i))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %s", named.Obj().Name(), st.Field(i).Name(), err)
+		}
+		if f != nil {
+			fields = append(fields, f)
+		}
+	}
+	var hasBody bool
+	for _, f := range fields {
+		for _, info := range f.infos {
+			switch info.in {
+			case inForm, inJSON, inProtobuf:
+				hasBody = true
+			}
+		}
+	}
+
+	typeName := named.Obj().Name()
+	g.printf("\n// BindRester binds the request parameters to %s without reflection.\n", typeName)
+	g.printf("func (a *%s) BindRester(ctx *fasthttp.RequestCtx) error {\n", typeName)
+	g.printf("r, err := binding.NewGenRequest(ctx, a, %t)\n", hasBody)
+	g.printf("if err != nil {\nreturn err\n}\n")
+	for _, f := range fields {
+		if err := g.genField(f); err != nil {
+			return fmt.Errorf("%s.%s: %s", typeName, f.name, err)
+		}
+	}
+	g.printf("_ = r\nreturn nil\n}\n")
+	return nil
+}
+
+// parseField parses the tags of the field like binding.Config, returns nil if the field is not bound.
+func parseField(v *types.Var, tag string) (*field, error) {
+	if v.Anonymous() {
+		if isBindingType(v.Type(), "NonStrict") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("embedded field is not supported")
+	}
+	structTag := reflect.StructTag(tag)
+	infos := make(map[string]*tagInfo)
+	omitted := make(map[string]bool)
+	for _, name := range tagNames {
+		value, ok := structTag.Lookup(name)
+		if !ok || name == tagVd {
+			continue
+		}
+		if name == inDefault {
+			infos[name] = &tagInfo{in: name, name: value}
+			continue
+		}
+		if value != "-" {
+			value = strings.Replace(strings.TrimSpace(value), " ", "", -1)
+			value = strings.Replace(value, "\t", "", -1)
+			if value == "" {
+				value = v.Name()
+			} else if value == ",required" {
+				value = v.Name() + value
+			}
+		}
+		info := splitTag(name, value)
+		if info.name == "-" {
+			omitted[name] = true
+			continue
+		}
+		infos[name] = info
+	}
+	f := &field{name: v.Name(), typ: v.Type()}
+	for _, in := range sortedIns {
+		if info := infos[in]; info != nil {
+			switch in {
+			case inXML, inRawBody, inCtx:
+				return nil, fmt.Errorf("the %s tag is not supported", in)
+			}
+			if info.secured != "" {
+				return nil, fmt.Errorf("the %s option is not supported", info.secured)
+			}
+			f.infos = append(f.infos, info)
+		}
+	}
+	if len(f.infos) == 0 {
+		for _, in := range defaultIns {
+			if !omitted[in] {
+				f.infos = append(f.infos, &tagInfo{in: in, name: v.Name()})
+			}
+		}
+	}
+	if len(f.infos) == 0 {
+		return nil, nil
+	}
+	f.namePath = v.Name()
+	for _, info := range f.infos {
+		if info.in == inJSON {
+			f.namePath = info.name
+		}
+	}
+	if isFileType(v.Type()) {
+		return nil, fmt.Errorf("file field is not supported")
+	}
+	if st, ok := derefType(v.Type()).Underlying().(*types.Struct); ok {
+		if len(infos) == 0 {
+			return nil, fmt.Errorf("struct field without tags is not supported")
+		}
+		if err := checkNestedTags(st, make(map[*types.Struct]bool)); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func splitTag(in, value string) *tagInfo {
+	info := &tagInfo{in: in}
+	for i, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		switch {
+		case i == 0:
+			info.name = s
+		case s == "required" || s == "req":
+			info.required = true
+		case s == "signed" || s == "encrypted":
+			info.secured = s
+		case strings.HasPrefix(s, "style="):
+			switch style := s[len("style="):]; style {
+			case "comma", "pipe", "space", "multi":
+				info.style = style
+			}
+		}
+	}
+	return info
+}
+
+// checkNestedTags checks the fields of the nested struct have no tags except the optional json and vd.
+func checkNestedTags(st *types.Struct, visited map[*types.Struct]bool) error {
+	if visited[st] {
+		return nil
+	}
+	visited[st] = true
+	for i := 0; i < st.NumFields(); i++ {
+		tag := reflect.StructTag(st.Tag(i))
+		for _, name := range tagNames {
+			value, ok := tag.Lookup(name)
+			if !ok || name == tagVd {
+				continue
+			}
+			if name != inJSON || splitTag(name, value).required {
+				return fmt.Errorf("the %s tag of the nested field %s is not supported", name, st.Field(i).Name())
+			}
+		}
+		if sub, ok := derefType(st.Field(i).Type()).Underlying().(*types.Struct); ok {
+			if err := checkNestedTags(sub, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func derefType(t types.Type) types.Type {
+	for {
+		p, ok := t.(*types.Pointer)
+		if !ok {
+			return t
+		}
+		t = p.Elem()
+	}
+}
+
+// isFormattedType reports whether the type is time.Duration or implements encoding.TextUnmarshaler,
+// which is parsed in the special format by the binding.
+func isFormattedType(t types.Type) bool {
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Duration" {
+		return true
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "UnmarshalText")
+	_, isMethod := obj.(*types.Func)
+	return isMethod
+}
+
+func isBindingType(t types.Type, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == bindingPath && named.Obj().Name() == name
+}
+
+func isFileType(t types.Type) bool {
+	if s, ok := t.(*types.Slice); ok {
+		t = s.Elem()
+	}
+	p, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := p.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	path, name := named.Obj().Pkg().Path(), named.Obj().Name()
+	return (path == "mime/multipart" && name == "FileHeader") || (path == bindingPath && name == "File")
+}
+
+// genField generates the binding of the field, which tries the positions in order like the reflective binding:
+// the first found position is used, and the error is returned if it is found or the last one.
+func (g *generator) genField(f *field) error {
+	g.printf("// %s\n", f.name)
+	g.printf("if err := func() (err error) {\n")
+	if len(f.infos) > 1 {
+		g.printf("var found bool\n")
+	}
+	for i, info := range f.infos {
+		last := i == len(f.infos)-1
+		found := "found"
+		if last {
+			found = "_"
+		}
+		requiredErr := "nil"
+		if info.required {
+			requiredErr = fmt.Sprintf("binding.GenRequiredError(%q)", f.namePath)
+		}
+		g.printf("// %s\n", info.in)
+		switch info.in {
+		case inPath, inQuery, inHeader, inCookie:
+			var values string
+			switch info.in {
+			case inPath:
+				values = fmt.Sprintf("r.Path(%q)", info.name)
+			case inQuery:
+				values = fmt.Sprintf("r.Query(%q, %q)", info.name, info.style)
+			case inHeader:
+				values = fmt.Sprintf("r.Header(%q)", info.name)
+			case inCookie:
+				values = fmt.Sprintf("r.Cookie(%q)", info.name)
+			}
+			g.printf("if vals := %s; len(vals) > 0 {\n%s, err = true, nil\n", values, found)
+			if err := g.genSetValues(f); err != nil {
+				return err
+			}
+			g.printf("} else {\n%s, err = false, %s\n}\n", found, requiredErr)
+			if info.in == inCookie {
+				// the missing optional cookie is treated as found
+				g.printf("%s = err == nil\n", found)
+			}
+		case inForm:
+			g.printf("if vals, ok := r.Form(%q, %q); ok {\n", info.name, info.style)
+			g.printf("if len(vals) > 0 {\n%s, err = true, nil\n", found)
+			if err := g.genSetValues(f); err != nil {
+				return err
+			}
+			g.printf("} else {\n%s, err = false, %s\n}\n", found, requiredErr)
+			g.genNotBody(found, f, info)
+		case inJSON:
+			g.printf("if exists, ok := r.JSON(%q); ok {\n", f.namePath)
+			g.printf("if exists {\n%s, err = true, nil\n} else {\n%s, err = false, %s\n}\n", found, found, requiredErr)
+			g.genNotBody(found, f, info)
+		case inProtobuf:
+			g.printf("if r.Protobuf() {\n%s, err = true, nil\n", found)
+			g.genNotBody(found, f, info)
+		case inDefault:
+			g.printf("%s, err = binding.GenDefault(&a.%s, %q)\n", found, f.name, info.name)
+		}
+		if last {
+			g.printf("return err\n")
+		} else {
+			g.printf("if found {\nreturn err\n}\n")
+		}
+	}
+	g.printf("}(); err != nil {\nreturn err\n}\n")
+	return nil
+}
+
+// genNotBody generates the branch when the body is not of the position,
+// the previous error is kept if the field is not required.
+func (g *generator) genNotBody(found string, f *field, info *tagInfo) {
+	if info.required {
+		g.printf("} else {\n%s, err = false, binding.GenRequiredError(%q)\n}\n", found, f.namePath)
+	} else if found == "_" {
+		g.printf("}\n")
+	} else {
+		g.printf("} else {\n%s = false\n}\n", found)
+	}
+}
+
+// genSetValues generates the conversion of the string values 'vals' to the field like binding.setStringsValue,
+// the empty string is converted to zero value.
+func (g *generator) genSetValues(f *field) error {
+	t := f.typ
+	target := "a." + f.name
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+		if _, ok := t.(*types.Pointer); ok {
+			return fmt.Errorf("multi-level pointer %s is not supported", g.typeString(f.typ))
+		}
+		g.printf("if %s == nil {\n%s = new(%s)\n}\n", target, target, g.typeString(t))
+		target = "*" + target
+	}
+	if isFormattedType(t) {
+		return fmt.Errorf("type %s is not supported", g.typeString(t))
+	}
+	typeErr := fmt.Sprintf("binding.GenTypeError(%q)", f.namePath)
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		typ := g.typeString(t)
+		var parse string
+		switch u.Kind() {
+		case types.String:
+			g.printf("%s = %s(vals[0])\n", target, typ)
+			return nil
+		case types.Bool:
+			parse = "strconv.ParseBool(vals[0])"
+		case types.Int, types.Int64:
+			parse = "strconv.ParseInt(vals[0], 10, 64)"
+		case types.Int32:
+			parse = "strconv.ParseInt(vals[0], 10, 32)"
+		case types.Int16:
+			parse = "strconv.ParseInt(vals[0], 10, 16)"
+		case types.Int8:
+			parse = "strconv.ParseInt(vals[0], 10, 8)"
+		case types.Uint, types.Uint64:
+			parse = "strconv.ParseUint(vals[0], 10, 64)"
+		case types.Uint32:
+			parse = "strconv.ParseUint(vals[0], 10, 32)"
+		case types.Uint16:
+			parse = "strconv.ParseUint(vals[0], 10, 16)"
+		case types.Uint8:
+			parse = "strconv.ParseUint(vals[0], 10, 8)"
+		case types.Float32:
+			parse = "strconv.ParseFloat(vals[0], 32)"
+		case types.Float64:
+			parse = "strconv.ParseFloat(vals[0], 64)"
+		default:
+			return fmt.Errorf("type %s is not supported", typ)
+		}
+		g.imports["strconv"] = "strconv"
+		g.printf("if v, e := %s; e == nil || vals[0] == \"\" {\n%s = %s(v)\n} else {\nerr = %s\n}\n", parse, target, typ, typeErr)
+		return nil
+	case *types.Slice:
+		elem, ok := u.Elem().(*types.Basic)
+		if !ok {
+			return fmt.Errorf("type %s is not supported", g.typeString(t))
+		}
+		if elem.Kind() == types.String {
+			g.printf("%s = vals\n", target)
+			return nil
+		}
+		fn, ok := stringsToFuncs[elem.Kind()]
+		if !ok {
+			return fmt.Errorf("type %s is not supported", g.typeString(t))
+		}
+		g.imports["github.com/henrylee2cn/goutil"] = "goutil"
+		g.printf("if v, e := goutil.%s(vals, true); e == nil {\n%s = v\n} else {\nerr = %s\n}\n", fn, target, typeErr)
+		return nil
+	default:
+		return fmt.Errorf("type %s is not supported", g.typeString(t))
+	}
+}
+
+var stringsToFuncs = map[types.BasicKind]string{
+	types.Bool:    "StringsToBools",
+	types.Float32: "StringsToFloat32s",
+	types.Float64: "StringsToFloat64s",
+	types.Int:     "StringsToInts",
+	types.Int64:   "StringsToInt64s",
+	types.Int32:   "StringsToInt32s",
+	types.Int16:   "StringsToInt16s",
+	types.Int8:    "StringsToInt8s",
+	types.Uint:    "StringsToUints",
+	types.Uint64:  "StringsToUint64s",
+	types.Uint32:  "StringsToUint32s",
+	types.Uint16:  "StringsToUint16s",
+	types.Uint8:   "StringsToUint8s",
+}
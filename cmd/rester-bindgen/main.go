@@ -0,0 +1,119 @@
+// Command rester-bindgen generates the BindRester methods of the argument structs,
+// which bind the request parameters without reflection, for example:
+//   //go:generate rester-bindgen -type=CreateUserArgs,ListUserArgs
+//
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of the struct type names; must be set")
+	output    = flag.String("output", "", "output file name; default <dir>/<type>_bindrester.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of rester-bindgen:\n")
+	fmt.Fprintf(os.Stderr, "\trester-bindgen -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("rester-bindgen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+	names := strings.Split(*typeNames, ",")
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(names[0])+"_bindrester.go")
+	}
+	src, err := generate(dir, names, filepath.Base(outputName))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = ioutil.WriteFile(outputName, src, 0644); err != nil {
+		log.Fatalf("writing output: %s", err)
+	}
+}
+
+// the imported packages are type-checked from source and cached
+var (
+	fset           = token.NewFileSet()
+	sourceImporter = importer.ForCompiler(fset, "source", nil)
+)
+
+// generate type-checks the package in the directory, and generates the source of the BindRester methods,
+// the output file is excluded from the package.
+func generate(dir string, names []string, outputFile string) ([]byte, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, name := range pkg.GoFiles {
+		if name == outputFile {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: sourceImporter}
+	tpkg, err := conf.Check(pkg.ImportPath, fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	g := newGenerator(tpkg)
+	for _, name := range names {
+		obj := tpkg.Scope().Lookup(name)
+		if obj == nil {
+			return nil, fmt.Errorf("type %s is not found", name)
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a named type", name)
+		}
+		if err = g.generate(named); err != nil {
+			return nil, err
+		}
+	}
+	return g.source()
+}
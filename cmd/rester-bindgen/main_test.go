@@ -0,0 +1,121 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester/binding"
+	"github.com/henrylee2cn/rester/cmd/rester-bindgen/testdata/args"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("testdata/args", []string{"Args", "Empty"}, "args_bindrester.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+	golden, err := ioutil.ReadFile("testdata/args/args_bindrester.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(golden), string(src))
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	_, err := generate("testdata/args", []string{"Unsupported"}, "args_bindrester.go")
+	assert.EqualError(t, err, "Unsupported.Body: the raw_body tag is not supported")
+	_, err = generate("testdata/args", []string{"Nested"}, "args_bindrester.go")
+	assert.EqualError(t, err, "Nested.Inner: the query tag of the nested field A is not supported")
+	_, err = generate("testdata/args", []string{"Timeout"}, "args_bindrester.go")
+	assert.EqualError(t, err, "Timeout.Timeout: type time.Duration is not supported")
+	_, err = generate("testdata/args", []string{"Session"}, "args_bindrester.go")
+	assert.EqualError(t, err, "Session.Session: the signed option is not supported")
+}
+
+// reflectArgs has the same fields as args.Args but no BindRester method
+type reflectArgs args.Args
+
+func TestBindRester(t *testing.T) {
+	type request struct {
+		uri         string
+		id          interface{}
+		header      map[string]string
+		contentType string
+		body        string
+	}
+	var cases = []request{
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}},
+		{uri: "/", header: map[string]string{"X-Token": "t"}},
+		{uri: "/", id: "x", header: map[string]string{"X-Token": "t"}},
+		{uri: "/", id: "", header: map[string]string{"X-Token": "t"}},
+		{uri: "/", id: 1, header: map[string]string{"X-Token": "t"}},
+		{uri: "/", id: "1"},
+		{uri: "/", id: "1", header: map[string]string{"Cookie": "token=c; session=s"}},
+		{
+			uri:    "/?page=2&size=3&level=4&tags=a,b&ids=1|2&ratio=0.5&enabled=true&internal=5&limit=6&Note=n&Kind=k",
+			id:     "1",
+			header: map[string]string{"X-Token": "t", "X-Level": "9"},
+		},
+		{uri: "/?page=&size=&level=&ratio=&enabled=", id: "1", header: map[string]string{"X-Token": "t"}},
+		{uri: "/?size=300", id: "1", header: map[string]string{"X-Token": "t"}},
+		{uri: "/?ids=1|x", id: "1", header: map[string]string{"X-Token": "t"}},
+		{uri: "/?enabled=yes", id: "1", header: map[string]string{"X-Token": "t"}},
+		{uri: "/", id: "1", header: map[string]string{"X-Level": "9", "X-Token": "t"}},
+		{uri: "/?limit=200", id: "1", header: map[string]string{"X-Token": "t"}},
+		{
+			uri:         "/",
+			id:          "1",
+			header:      map[string]string{"X-Token": "t"},
+			contentType: "application/json",
+			body:        `{"name":"n","email":"e","created":"2020-01-02T03:04:05Z","profile":{"age":1,"city":"c"},"extra":{"b":2},"Note":"x","internal":1}`,
+		},
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/json", body: `{"email":"e"}`},
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/json", body: `{"name":1}`},
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/json", body: `{"name":"n","profile":{"age":-1}}`},
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/x-www-form-urlencoded", body: "name=n&enabled=1&Note=x"},
+		{uri: "/?enabled=true", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/x-www-form-urlencoded", body: "enabled=x"},
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/x-www-form-urlencoded", body: "enabled=1"},
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/xml", body: "<Args><Note>x</Note><Name>n</Name></Args>"},
+		{uri: "/", id: "1", header: map[string]string{"X-Token": "t"}, contentType: "application/x-protobuf", body: "x"},
+	}
+	newCtx := func(c request) *fasthttp.RequestCtx {
+		ctx := new(fasthttp.RequestCtx)
+		ctx.Request.SetRequestURI(c.uri)
+		ctx.Request.Header.SetMethod("POST")
+		for k, v := range c.header {
+			ctx.Request.Header.Set(k, v)
+		}
+		if c.id != nil {
+			ctx.SetUserValue("id", c.id)
+		}
+		if c.contentType != "" {
+			ctx.Request.Header.SetContentType(c.contentType)
+			ctx.Request.SetBodyString(c.body)
+		}
+		return ctx
+	}
+	errString := func(err error) string {
+		if err == nil {
+			return ""
+		}
+		return strings.Replace(err.Error(), "reflectArgs", "Args", -1)
+	}
+	b := binding.New(nil).SetLooseZeroMode(true)
+	for i, c := range cases {
+		want := new(reflectArgs)
+		wantErr := b.Bind(want, newCtx(c))
+		got := new(args.Args)
+		gotErr := b.Bind(got, newCtx(c))
+		direct := new(args.Args)
+		directErr := direct.BindRester(newCtx(c))
+		assert.Equal(t, (*args.Args)(want), got, "case %d", i)
+		assert.Equal(t, (*args.Args)(want), direct, "case %d", i)
+		assert.Equal(t, errString(wantErr), errString(gotErr), "case %d", i)
+		assert.Equal(t, errString(wantErr), errString(directErr), "case %d", i)
+
+		wantErr = b.BindAndValidate(new(reflectArgs), newCtx(c))
+		gotErr = b.BindAndValidate(new(args.Args), newCtx(c))
+		assert.Equal(t, errString(wantErr), errString(gotErr), "case %d", i)
+	}
+}
@@ -0,0 +1,62 @@
+package args
+
+import (
+	"time"
+
+	"github.com/henrylee2cn/rester/binding"
+)
+
+//go:generate go run ../.. -type Args,Empty
+
+type Level int
+
+type Tags []string
+
+type Args struct {
+	binding.NonStrict
+	ID       int64          `path:"id,required"`
+	Page     *int           `query:"page" default:"1"`
+	Size     uint8          `query:"size"`
+	Level    Level          `query:"level" header:"X-Level"`
+	Tags     Tags           `query:"tags,style=comma"`
+	IDs      []int          `query:"ids,style=pipe"`
+	Ratio    float32        `query:"ratio"`
+	Enabled  bool           `form:"enabled" query:"enabled"`
+	Token    string         `header:"X-Token,required" cookie:"token"`
+	Session  string         `cookie:"session"`
+	Name     string         `json:"name,required" form:"name"`
+	Email    *string        `json:"email"`
+	Created  time.Time      `json:"created"`
+	Profile  *Profile       `json:"profile"`
+	Extra    map[string]int `json:"extra" default:"{'a':1}"`
+	Kind     string         `default:"user"`
+	Note     string
+	Ignored  string `json:"-" query:"-" form:"-" path:"-" cookie:"-" header:"-" protobuf:"-"`
+	internal int    `query:"internal"`
+	Limit    int    `vd:"$<=100" query:"limit"`
+}
+
+type Profile struct {
+	Age  int    `json:"age" vd:"$>=0"`
+	City string `json:"city"`
+}
+
+type Empty struct{}
+
+type Unsupported struct {
+	Body []byte `raw_body:""`
+}
+
+type Nested struct {
+	Inner struct {
+		A int `query:"a"`
+	} `json:"inner"`
+}
+
+type Timeout struct {
+	Timeout time.Duration `query:"timeout"`
+}
+
+type Session struct {
+	Session string `cookie:"session,signed"`
+}
@@ -0,0 +1,461 @@
+// Code generated by rester-bindgen; DO NOT EDIT.
+
+package args
+
+import (
+	"strconv"
+
+	"github.com/henrylee2cn/goutil"
+	"github.com/henrylee2cn/rester/binding"
+	"github.com/valyala/fasthttp"
+)
+
+// BindRester binds the request parameters to Args without reflection.
+func (a *Args) BindRester(ctx *fasthttp.RequestCtx) error {
+	r, err := binding.NewGenRequest(ctx, a, true)
+	if err != nil {
+		return err
+	}
+	// ID
+	if err := func() (err error) {
+		// path
+		if vals := r.Path("id"); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := strconv.ParseInt(vals[0], 10, 64); e == nil || vals[0] == "" {
+				a.ID = int64(v)
+			} else {
+				err = binding.GenTypeError("ID")
+			}
+		} else {
+			_, err = false, binding.GenRequiredError("ID")
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Page
+	if err := func() (err error) {
+		var found bool
+		// query
+		if vals := r.Query("page", ""); len(vals) > 0 {
+			found, err = true, nil
+			if a.Page == nil {
+				a.Page = new(int)
+			}
+			if v, e := strconv.ParseInt(vals[0], 10, 64); e == nil || vals[0] == "" {
+				*a.Page = int(v)
+			} else {
+				err = binding.GenTypeError("Page")
+			}
+		} else {
+			found, err = false, nil
+		}
+		if found {
+			return err
+		}
+		// default
+		_, err = binding.GenDefault(&a.Page, "1")
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Size
+	if err := func() (err error) {
+		// query
+		if vals := r.Query("size", ""); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := strconv.ParseUint(vals[0], 10, 8); e == nil || vals[0] == "" {
+				a.Size = uint8(v)
+			} else {
+				err = binding.GenTypeError("Size")
+			}
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Level
+	if err := func() (err error) {
+		var found bool
+		// query
+		if vals := r.Query("level", ""); len(vals) > 0 {
+			found, err = true, nil
+			if v, e := strconv.ParseInt(vals[0], 10, 64); e == nil || vals[0] == "" {
+				a.Level = Level(v)
+			} else {
+				err = binding.GenTypeError("Level")
+			}
+		} else {
+			found, err = false, nil
+		}
+		if found {
+			return err
+		}
+		// header
+		if vals := r.Header("X-Level"); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := strconv.ParseInt(vals[0], 10, 64); e == nil || vals[0] == "" {
+				a.Level = Level(v)
+			} else {
+				err = binding.GenTypeError("Level")
+			}
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Tags
+	if err := func() (err error) {
+		// query
+		if vals := r.Query("tags", "comma"); len(vals) > 0 {
+			_, err = true, nil
+			a.Tags = vals
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// IDs
+	if err := func() (err error) {
+		// query
+		if vals := r.Query("ids", "pipe"); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := goutil.StringsToInts(vals, true); e == nil {
+				a.IDs = v
+			} else {
+				err = binding.GenTypeError("IDs")
+			}
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Ratio
+	if err := func() (err error) {
+		// query
+		if vals := r.Query("ratio", ""); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := strconv.ParseFloat(vals[0], 32); e == nil || vals[0] == "" {
+				a.Ratio = float32(v)
+			} else {
+				err = binding.GenTypeError("Ratio")
+			}
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Enabled
+	if err := func() (err error) {
+		var found bool
+		// form
+		if vals, ok := r.Form("enabled", ""); ok {
+			if len(vals) > 0 {
+				found, err = true, nil
+				if v, e := strconv.ParseBool(vals[0]); e == nil || vals[0] == "" {
+					a.Enabled = bool(v)
+				} else {
+					err = binding.GenTypeError("Enabled")
+				}
+			} else {
+				found, err = false, nil
+			}
+		} else {
+			found = false
+		}
+		if found {
+			return err
+		}
+		// query
+		if vals := r.Query("enabled", ""); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := strconv.ParseBool(vals[0]); e == nil || vals[0] == "" {
+				a.Enabled = bool(v)
+			} else {
+				err = binding.GenTypeError("Enabled")
+			}
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Token
+	if err := func() (err error) {
+		var found bool
+		// cookie
+		if vals := r.Cookie("token"); len(vals) > 0 {
+			found, err = true, nil
+			a.Token = string(vals[0])
+		} else {
+			found, err = false, nil
+		}
+		found = err == nil
+		if found {
+			return err
+		}
+		// header
+		if vals := r.Header("X-Token"); len(vals) > 0 {
+			_, err = true, nil
+			a.Token = string(vals[0])
+		} else {
+			_, err = false, binding.GenRequiredError("Token")
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Session
+	if err := func() (err error) {
+		// cookie
+		if vals := r.Cookie("session"); len(vals) > 0 {
+			_, err = true, nil
+			a.Session = string(vals[0])
+		} else {
+			_, err = false, nil
+		}
+		_ = err == nil
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Name
+	if err := func() (err error) {
+		var found bool
+		// form
+		if vals, ok := r.Form("name", ""); ok {
+			if len(vals) > 0 {
+				found, err = true, nil
+				a.Name = string(vals[0])
+			} else {
+				found, err = false, nil
+			}
+		} else {
+			found = false
+		}
+		if found {
+			return err
+		}
+		// json
+		if exists, ok := r.JSON("name"); ok {
+			if exists {
+				_, err = true, nil
+			} else {
+				_, err = false, binding.GenRequiredError("name")
+			}
+		} else {
+			_, err = false, binding.GenRequiredError("name")
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Email
+	if err := func() (err error) {
+		// json
+		if exists, ok := r.JSON("email"); ok {
+			if exists {
+				_, err = true, nil
+			} else {
+				_, err = false, nil
+			}
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Created
+	if err := func() (err error) {
+		// json
+		if exists, ok := r.JSON("created"); ok {
+			if exists {
+				_, err = true, nil
+			} else {
+				_, err = false, nil
+			}
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Profile
+	if err := func() (err error) {
+		// json
+		if exists, ok := r.JSON("profile"); ok {
+			if exists {
+				_, err = true, nil
+			} else {
+				_, err = false, nil
+			}
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Extra
+	if err := func() (err error) {
+		var found bool
+		// json
+		if exists, ok := r.JSON("extra"); ok {
+			if exists {
+				found, err = true, nil
+			} else {
+				found, err = false, nil
+			}
+		} else {
+			found = false
+		}
+		if found {
+			return err
+		}
+		// default
+		_, err = binding.GenDefault(&a.Extra, "{'a':1}")
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Kind
+	if err := func() (err error) {
+		// default
+		_, err = binding.GenDefault(&a.Kind, "user")
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Note
+	if err := func() (err error) {
+		var found bool
+		// path
+		if vals := r.Path("Note"); len(vals) > 0 {
+			found, err = true, nil
+			a.Note = string(vals[0])
+		} else {
+			found, err = false, nil
+		}
+		if found {
+			return err
+		}
+		// form
+		if vals, ok := r.Form("Note", ""); ok {
+			if len(vals) > 0 {
+				found, err = true, nil
+				a.Note = string(vals[0])
+			} else {
+				found, err = false, nil
+			}
+		} else {
+			found = false
+		}
+		if found {
+			return err
+		}
+		// query
+		if vals := r.Query("Note", ""); len(vals) > 0 {
+			found, err = true, nil
+			a.Note = string(vals[0])
+		} else {
+			found, err = false, nil
+		}
+		if found {
+			return err
+		}
+		// cookie
+		if vals := r.Cookie("Note"); len(vals) > 0 {
+			found, err = true, nil
+			a.Note = string(vals[0])
+		} else {
+			found, err = false, nil
+		}
+		found = err == nil
+		if found {
+			return err
+		}
+		// header
+		if vals := r.Header("Note"); len(vals) > 0 {
+			found, err = true, nil
+			a.Note = string(vals[0])
+		} else {
+			found, err = false, nil
+		}
+		if found {
+			return err
+		}
+		// protobuf
+		if r.Protobuf() {
+			found, err = true, nil
+		} else {
+			found = false
+		}
+		if found {
+			return err
+		}
+		// json
+		if exists, ok := r.JSON("Note"); ok {
+			if exists {
+				_, err = true, nil
+			} else {
+				_, err = false, nil
+			}
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// internal
+	if err := func() (err error) {
+		// query
+		if vals := r.Query("internal", ""); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := strconv.ParseInt(vals[0], 10, 64); e == nil || vals[0] == "" {
+				a.internal = int(v)
+			} else {
+				err = binding.GenTypeError("internal")
+			}
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	// Limit
+	if err := func() (err error) {
+		// query
+		if vals := r.Query("limit", ""); len(vals) > 0 {
+			_, err = true, nil
+			if v, e := strconv.ParseInt(vals[0], 10, 64); e == nil || vals[0] == "" {
+				a.Limit = int(v)
+			} else {
+				err = binding.GenTypeError("Limit")
+			}
+		} else {
+			_, err = false, nil
+		}
+		return err
+	}(); err != nil {
+		return err
+	}
+	_ = r
+	return nil
+}
+
+// BindRester binds the request parameters to Empty without reflection.
+func (a *Empty) BindRester(ctx *fasthttp.RequestCtx) error {
+	r, err := binding.NewGenRequest(ctx, a, false)
+	if err != nil {
+		return err
+	}
+	_ = r
+	return nil
+}
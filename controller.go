@@ -33,21 +33,26 @@ type (
 	Controller interface {
 		chain.NestedStruct
 		internal2(internalType)
-		setContext(*RequestCtx)
+		setContext(*RequestCtx, *Engine)
+		Accepted(value interface{})
 		BadRequest(code int, msg string)
+		Created(location string, value interface{})
 		Forbidden(code int, msg string)
 		InternalServerError(code int, msg string, err ...error)
+		NoContent()
 		NotFound(...string)
 		NotModified()
 		OK(value interface{})
 		QueryAllArray(key string) []string
 		Redirect(code int, location string)
+		Respond(status int, value interface{})
 		Unauthorized(code int, msg string)
 	}
 	// BaseCtl the base controller that each controller must inherit
 	BaseCtl struct {
 		chain.Base
 		*RequestCtx
+		engine *Engine
 	}
 	// CodeMsg response body when the http code is not 200
 	CodeMsg struct {
@@ -60,7 +65,12 @@ type (
 )
 
 var _ Controller = new(BaseCtl)
-var binder = binding.New(nil).SetLooseZeroMode(true)
+
+// NewBinding creates the binding with the default configuration of the framework,
+// which can be customized and set by Router.SetBinding.
+func NewBinding() *binding.Binding {
+	return binding.New(nil).SetLooseZeroMode(true)
+}
 
 // MustMakeHandlers creates map {httpMethod:RequestHandler} from the Controller factory.
 // NOTE:
@@ -89,62 +99,109 @@ func checkNewChainErr(err error) {
 // MakeHandlers creates map {httpMethod:RequestHandler} from the Controller factory.
 // NOTE: Any means all http methods
 func MakeHandlers(factory func() Controller) (map[string]RequestHandler, error) {
-	return newHandlers(nil, factory)
+	handlers, _, err := newHandlers(nil, factory, handlerOptions{})
+	return handlers, err
 }
 
 // NewHandlers converts the Controller to map {httpMethod:RequestHandler}.
 // NOTE: Any means all http methods
 func NewHandlers(c Controller) (map[string]RequestHandler, error) {
-	return newHandlers(c, nil)
+	handlers, _, err := newHandlers(c, nil, handlerOptions{})
+	return handlers, err
+}
+
+// handlerOptions the options to create handlers of controller
+type handlerOptions struct {
+	engine      *Engine
+	cors        *CORSConfig
+	binding     *binding.Binding
+	policy      Policy
+	middlewares []Controller
 }
 
-func newHandlers(c Controller, factory func() Controller) (map[string]RequestHandler, error) {
+// handlerChain the chain methods of the handler and the permissions they require
+type handlerChain struct {
+	// methods the chain methods in execution order, the last one is the controller's own method
+	methods     []reflect.Method
+	permissions []string
+}
+
+// newHandlers creates map {httpMethod:RequestHandler} and map {httpMethod:handlerChain}.
+func newHandlers(c Controller, factory func() Controller, opts handlerOptions) (map[string]RequestHandler, map[string]handlerChain, error) {
+	engine, middlewares := opts.engine, opts.middlewares
+	bind := opts.binding
+	if bind == nil {
+		bind = NewBinding()
+		if engine != nil {
+			bind.SetKeyring(engine.keyring)
+		}
+	}
 	handlers := make(map[string]RequestHandler)
+	chains := make(map[string]handlerChain)
 	corsMethods := make(map[string]struct{})
 	if factory != nil {
 		c = factory()
 	}
+	permissionsFor := permissionsOf(c)
 	var err error
 	for _, httpMethod := range httpMethodList {
 		var fn chain.Func
+		var found []reflect.Method
 		if factory != nil {
 			fn, err = chain.Make(func() chain.NestedStruct {
 				return factory()
-			}, newFinder(httpMethod))
+			}, newFinder(engine, httpMethod, false, &found))
 		} else {
-			fn, err = chain.New(c, newFinder(httpMethod))
+			fn, err = chain.New(c, newFinder(engine, httpMethod, false, &found))
 		}
 		switch err {
 		case nil:
 			var cors bool
 			httpMethod, cors = splitMethod(httpMethod)
+			permissions := permissionsFor(httpMethod)
+			if len(permissions) > 0 && opts.policy == nil {
+				return nil, nil, fmt.Errorf("%T requires permissions %v but no policy is set", c, permissions)
+			}
+			methods := reverseMethods(found)
+			if len(middlewares) > 0 {
+				var mwMethods []reflect.Method
+				fn, mwMethods, err = joinMiddlewares(engine, httpMethod, middlewares, fn)
+				if err != nil {
+					return nil, nil, err
+				}
+				methods = append(mwMethods, methods...)
+			}
+			chains[httpMethod] = handlerChain{methods: methods, permissions: permissions}
 			handlers[httpMethod] = func(ctx *RequestCtx) {
-				err := fn(argsRequestCtx{ctx})
-				switch e := err.(type) {
-				case nil:
-				case *CodeMsg:
-					if e.Code >= 400 && e.Code < 600 {
-						renderJSON(ctx, e.Code, e)
-					}
-				default:
-					renderJSON(ctx, fasthttp.StatusInternalServerError, e)
+				args := argsRequestCtx{RequestCtx: ctx, engine: engine, binding: bind, policy: opts.policy, permissions: permissions}
+				if engine != nil && len(engine.providers) > 0 {
+					args.scope = new(requestScope)
+					defer args.scope.cleanup()
 				}
+				renderError(engine, ctx, fn(args))
 			}
 			if cors {
 				corsMethods[httpMethod] = struct{}{}
 			}
 		case chain.ErrEmpty:
 		default:
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	if len(handlers) == 0 {
-		return nil, fmt.Errorf("%T has no method with the same name as HTTP method, eg. GET or CORS_GET", c)
+		return nil, nil, fmt.Errorf("%T has no method with the same name as HTTP method, eg. GET or CORS_GET", c)
 	}
 
 	if len(corsMethods) > 0 {
-		corsFn := newCorsFunc(corsMethods)
+		cors := opts.cors
+		if p, ok := c.(CORSProvider); ok {
+			cors = p.CORSConfig()
+		}
+		corsFn, err := newCorsFunc(corsMethods, cors)
+		if err != nil {
+			return nil, nil, err
+		}
 		if handlers["OPTIONS"] == nil {
 			handlers["OPTIONS"] = corsFn
 		}
@@ -156,93 +213,111 @@ func newHandlers(c Controller, factory func() Controller) (map[string]RequestHan
 			}
 		}
 	}
-	return handlers, nil
-}
-func newCorsFunc(corsMethods map[string]struct{}) RequestHandler {
-	var a []string
-	for m := range corsMethods {
-		a = append(a, m)
-	}
-	allowMethods := strings.Join(a, ", ")
-	return func(c *RequestCtx) {
-		c.Response.Header.SetBytesV("Access-Control-Allow-Origin", c.Request.Header.Peek("Origin"))
-		c.Response.Header.Set("Access-Control-Allow-Credentials", "true")
-		c.Response.Header.Set("Access-Control-Allow-Methods", allowMethods)
-		c.Response.Header.SetBytesV("Access-Control-Allow-Headers", c.Request.Header.Peek("Access-Control-Request-Headers"))
-		if c.Request.Header.IsOptions() {
-			c.SetStatusCode(fasthttp.StatusNoContent)
-			c.SetBodyString("")
-		}
-	}
+	return handlers, chains, nil
 }
 
 func (*BaseCtl) internal2(internalType) {}
 
-func (b *BaseCtl) setContext(c *RequestCtx) {
+func (b *BaseCtl) setContext(c *RequestCtx, engine *Engine) {
 	b.RequestCtx = c
+	b.engine = engine
 }
 
-func (b BaseCtl) BadRequest(code int, msg string) {
-	b.renderJSON(fasthttp.StatusBadRequest, CodeMsg{
-		Code: code,
-		Msg:  msg,
-	})
+func (b *BaseCtl) BadRequest(code int, msg string) {
+	b.renderCodeMsg(fasthttp.StatusBadRequest, code, msg)
 	b.Abort(nil)
 }
 
-func (b BaseCtl) InternalServerError(code int, msg string, err ...error) {
+func (b *BaseCtl) InternalServerError(code int, msg string, err ...error) {
 	if len(err) > 0 && err[0] != nil {
 		b.RequestCtx.Logger().Printf("msg=%s, error=%s", msg, err[0].Error())
 	}
-	b.renderJSON(fasthttp.StatusInternalServerError, CodeMsg{
-		Code: code,
-		Msg:  msg,
-	})
+	b.renderCodeMsg(fasthttp.StatusInternalServerError, code, msg)
 	b.Abort(nil)
 }
 
-func (b BaseCtl) NotFound(msg ...string) {
+func (b *BaseCtl) NotFound(msg ...string) {
 	msg = append(msg, "404 Page not found")
 	ctx := b.RequestCtx
 	ctx.Response.Reset()
-	ctx.SetStatusCode(fasthttp.StatusNotFound)
-	ctx.SetBodyString(msg[0])
+	if b.engine.problemJSON() {
+		renderProblem(ctx, NewProblem(fasthttp.StatusNotFound, msg[0]))
+	} else {
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+		ctx.SetBodyString(msg[0])
+	}
 	b.Abort(nil)
 }
 
 // NotModified resets response and sets '304 Not Modified' response status code.
-func (b BaseCtl) NotModified() {
+func (b *BaseCtl) NotModified() {
 	b.RequestCtx.NotModified()
 	b.Abort(nil)
 }
 
-func (b BaseCtl) Unauthorized(code int, msg string) {
-	b.renderJSON(fasthttp.StatusUnauthorized, CodeMsg{
-		Code: code,
-		Msg:  msg,
-	})
+func (b *BaseCtl) Unauthorized(code int, msg string) {
+	b.renderCodeMsg(fasthttp.StatusUnauthorized, code, msg)
 	b.Abort(nil)
 }
 
-func (b BaseCtl) Forbidden(code int, msg string) {
-	b.renderJSON(fasthttp.StatusForbidden, CodeMsg{
-		Code: code,
-		Msg:  msg,
-	})
+func (b *BaseCtl) Forbidden(code int, msg string) {
+	b.renderCodeMsg(fasthttp.StatusForbidden, code, msg)
 	b.Abort(nil)
 }
 
-func (b BaseCtl) Redirect(code int, location string) {
+func (b *BaseCtl) Redirect(code int, location string) {
 	b.RequestCtx.Redirect(location, code)
 	b.Abort(nil)
 }
 
-func (b BaseCtl) OK(value interface{}) {
-	b.renderJSON(fasthttp.StatusOK, value)
+// OK responds '200 OK' with the value encoded by the renderer negotiated from the 'Accept' header.
+// NOTE:
+//  The response struct is written by its 'status', 'header', 'cookie' and 'body' fields, e.g.
+//    type CreatedUser struct {
+//        Status   int      `status:""`
+//        Location string   `header:"Location,required"`
+//        Links    []string `header:"Link"`
+//        Body     *User    `body:""`
+//    }
+func (b *BaseCtl) OK(value interface{}) {
+	respond(b.engine, b.RequestCtx, fasthttp.StatusOK, value)
+}
+
+// Respond renders the value with the specified status code,
+// the renderer is negotiated from the 'Accept' header.
+// NOTE:
+//  If value is nil, only the status code is written;
+//  If no renderer is acceptable, responds '406 Not Acceptable';
+//  The non-zero 'status' field of the response struct overrides the status code.
+func (b *BaseCtl) Respond(status int, value interface{}) {
+	if value == nil {
+		b.RequestCtx.SetStatusCode(status)
+		b.RequestCtx.ResetBody()
+		return
+	}
+	respond(b.engine, b.RequestCtx, status, value)
+}
+
+// Created sets the 'Location' header and responds '201 Created' with the value.
+func (b *BaseCtl) Created(location string, value interface{}) {
+	if location != "" {
+		b.RequestCtx.Response.Header.Set(fasthttp.HeaderLocation, location)
+	}
+	b.Respond(fasthttp.StatusCreated, value)
+}
+
+// Accepted responds '202 Accepted' with the value.
+func (b *BaseCtl) Accepted(value interface{}) {
+	b.Respond(fasthttp.StatusAccepted, value)
+}
+
+// NoContent responds '204 No Content' without body.
+func (b *BaseCtl) NoContent() {
+	b.Respond(fasthttp.StatusNoContent, nil)
 }
 
 // QueryAllArray gets ["1","2","3","4","5"] from a=1,2,3&a=4&a=5
-func (b BaseCtl) QueryAllArray(key string) []string {
+func (b *BaseCtl) QueryAllArray(key string) []string {
 	if b.RequestCtx == nil {
 		return nil
 	}
@@ -256,25 +331,41 @@ func (b BaseCtl) QueryAllArray(key string) []string {
 }
 
 // IsAjaxRequest front-end setup required
-func (b BaseCtl) IsAjaxRequest() bool {
+func (b *BaseCtl) IsAjaxRequest() bool {
 	return ameda.UnsafeBytesToString(b.RequestCtx.Request.Header.Peek("X-Requested-With")) == "XMLHttpRequest"
 }
 
-func (b BaseCtl) renderJSON(code int, body interface{}) {
+func (b *BaseCtl) renderJSON(code int, body interface{}) {
 	renderJSON(b.RequestCtx, code, body)
 }
 
+// renderCodeMsg renders CodeMsg, or Problem if Engine.ProblemJSON is enabled.
+func (b *BaseCtl) renderCodeMsg(status, code int, msg string) {
+	if b.engine.problemJSON() {
+		renderProblem(b.RequestCtx, codeMsgProblem(status, &CodeMsg{Code: code, Msg: msg}))
+		return
+	}
+	b.renderJSON(status, CodeMsg{
+		Code: code,
+		Msg:  msg,
+	})
+}
+
 var useTestMode = true
 
 const jsonContentType = "application/json; charset=utf-8"
 
 func renderJSON(ctx *RequestCtx, code int, body interface{}) {
+	renderJSONAs(ctx, code, jsonContentType, body)
+}
+
+func renderJSONAs(ctx *RequestCtx, code int, contentType string, body interface{}) {
 	if useTestMode && goutil.IsGoTest() {
 		b, _ := json.MarshalIndent(body, "", "  ")
-		fmt.Printf("Respond: status_code=%d, json_body=%s\n", code, b)
+		printTestResponse(code, contentType, b)
 		return
 	}
-	ctx.SetContentType(jsonContentType)
+	ctx.SetContentType(contentType)
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
@@ -285,10 +376,70 @@ func renderJSON(ctx *RequestCtx, code int, body interface{}) {
 		ctx.SetBody(bodyBytes)
 		return
 	}
-	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetStatusCode(code)
 	ctx.SetBody(bodyBytes)
 }
 
+// renderBytes writes the encoded body, or prints it in the test mode.
+func renderBytes(ctx *RequestCtx, code int, contentType string, body []byte) {
+	if useTestMode && goutil.IsGoTest() {
+		printTestResponse(code, contentType, body)
+		return
+	}
+	ctx.SetContentType(contentType)
+	ctx.SetStatusCode(code)
+	ctx.SetBody(body)
+}
+
+// printTestResponse prints the response instead of writing it in the test mode.
+func printTestResponse(code int, contentType string, body []byte) {
+	if strings.HasSuffix(parseMediaType(contentType), "json") {
+		fmt.Printf("Respond: status_code=%d, json_body=%s\n", code, body)
+		return
+	}
+	fmt.Printf("Respond: status_code=%d, content_type=%s, body=%q\n", code, contentType, body)
+}
+
+func renderError(engine *Engine, ctx *RequestCtx, err error) {
+	if err == nil {
+		return
+	}
+	var status int
+	var body interface{}
+	var mapped bool
+	switch e := err.(type) {
+	case *Problem:
+		renderProblem(ctx, e)
+		return
+	case *CodeMsg:
+		if e.Code < 400 || e.Code >= 600 {
+			return
+		}
+		status, body = e.Code, e
+	case *bindingError:
+		status, body = fasthttp.StatusBadRequest, &CodeMsg{Code: fasthttp.StatusBadRequest, Msg: e.Error()}
+	default:
+		status, body = fasthttp.StatusInternalServerError, e
+		if engine != nil && engine.ErrorMapper != nil {
+			if s, b := engine.ErrorMapper(err); s != 0 {
+				status, body, mapped = s, b, true
+				if body == nil {
+					body = &CodeMsg{Code: status, Msg: err.Error()}
+				}
+			}
+		}
+	}
+	if p, ok := body.(*Problem); ok {
+		renderProblem(ctx, p)
+		return
+	}
+	if engine.problemJSON() {
+		renderProblem(ctx, errorProblem(ctx, status, err, body, mapped))
+		return
+	}
+	renderJSON(ctx, status, body)
+}
+
 var _ error = new(CodeMsg)
 
 func (c *CodeMsg) Error() string {
@@ -300,15 +451,45 @@ func (c *CodeMsg) Error() string {
 
 type argsRequestCtx struct {
 	*RequestCtx
+	engine      *Engine
+	binding     *binding.Binding
+	scope       *requestScope
+	policy      Policy
+	permissions []string
 }
 
+var (
+	_ chain.ResultHandler = argsRequestCtx{}
+	_ chain.Guard         = argsRequestCtx{}
+)
+
 func (a argsRequestCtx) Init(recv chain.NestedStruct) error {
 	c := recv.(Controller)
-	c.setContext(a.RequestCtx)
+	c.setContext(a.RequestCtx, a.engine)
 	return nil
 }
 
+// HandleResult renders the non-nil result returned by the controller method.
+func (a argsRequestCtx) HandleResult(_ reflect.Type, result reflect.Value) error {
+	switch result.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if result.IsNil() {
+			return nil
+		}
+	}
+	respond(a.engine, a.RequestCtx, fasthttp.StatusOK, result.Interface())
+	return nil
+}
+
+// Arg injects the argument of the provided type, or binds the first argument from the request.
 func (a argsRequestCtx) Arg(recvType reflect.Type, idx int, in reflect.Type) (reflect.Value, error) {
+	// the scope is created by the handler if the engine has any provider
+	if p := a.engine.provider(in); p != nil {
+		return a.scope.inject(p, a.RequestCtx)
+	}
+	if idx > 0 {
+		return reflect.Value{}, fmt.Errorf("no provider of %s for the argument %d of %s", in, idx+1, recvType)
+	}
 	var ptrNum int
 	for in.Kind() == reflect.Ptr {
 		in = in.Elem()
@@ -316,20 +497,62 @@ func (a argsRequestCtx) Arg(recvType reflect.Type, idx int, in reflect.Type) (re
 	}
 	vPtr := reflect.New(in)
 	reqRecvPtr := vPtr.Interface()
-	err := binder.BindAndValidate(reqRecvPtr, a.RequestCtx)
+	err := a.binding.BindAndValidate(reqRecvPtr, a.RequestCtx)
 	if err != nil {
-		return reflect.Value{}, &CodeMsg{400, err.Error()}
+		return reflect.Value{}, &bindingError{err}
 	}
 	return ameda.ReferenceValue(vPtr, ptrNum-1), nil
 }
 
-func newFinder(httpMethod string) chain.FindFunc {
+// joinMiddlewares creates the function that executes the middleware chains before fn,
+// and returns the methods of the middleware chains in execution order.
+func joinMiddlewares(engine *Engine, httpMethod string, middlewares []Controller, fn chain.Func) (chain.Func, []reflect.Method, error) {
+	fns := make([]chain.Func, 0, len(middlewares)+1)
+	var methods []reflect.Method
+	for _, mw := range middlewares {
+		var found []reflect.Method
+		mwFn, err := chain.Make(copyFactory(mw), newFinder(engine, httpMethod, true, &found))
+		switch err {
+		case nil:
+			fns = append(fns, mwFn)
+			methods = append(methods, reverseMethods(found)...)
+		case chain.ErrEmpty:
+		default:
+			return nil, nil, err
+		}
+	}
+	return chain.Join(append(fns, fn)...), methods, nil
+}
+
+// copyFactory creates a factory that returns a shallow copy of the controller.
+func copyFactory(c Controller) chain.FactoryFunc {
+	v := ameda.DereferencePtrValue(reflect.ValueOf(c))
+	return func() chain.NestedStruct {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(chain.NestedStruct)
+	}
+}
+
+// reverseMethods converts the found methods to the execution order of chain.
+func reverseMethods(found []reflect.Method) []reflect.Method {
+	methods := make([]reflect.Method, len(found))
+	for i, m := range found {
+		methods[len(found)-1-i] = m
+	}
+	return methods
+}
+
+// newFinder creates the chain.FindFunc, and appends the found methods to found if it is not nil.
+// NOTE:
+//  The arguments after the first one must be provided by the engine.
+func newFinder(engine *Engine, httpMethod string, middleware bool, found *[]reflect.Method) chain.FindFunc {
 	findMethod := chain.FindName(httpMethod)
 	findAny := chain.FindName(anyMethod)
 	return func(level int, methods []reflect.Method) (m *reflect.Method, err error) {
 		m, err = findMethod(level, methods)
 		if m == nil && err == nil {
-			if level == 0 {
+			if level == 0 && !middleware {
 				return nil, chain.ErrEmpty
 			}
 			m, err = findAny(level, methods)
@@ -337,9 +560,25 @@ func newFinder(httpMethod string) chain.FindFunc {
 		if m == nil || err != nil {
 			return
 		}
-		if m.Type.NumIn() > 2 {
-			return nil, fmt.Errorf("%s.%s has more than two input parameters", m.Type.In(0).String(), m.Name)
+		if err = checkProvided(engine, m); err != nil {
+			return nil, err
+		}
+		if found != nil {
+			*found = append(*found, *m)
 		}
 		return m, nil
 	}
 }
+
+// checkProvided checks that the arguments of the method after the first one are provided by the engine.
+func checkProvided(engine *Engine, m *reflect.Method) error {
+	for i := 2; i < m.Type.NumIn(); i++ {
+		if engine == nil {
+			return fmt.Errorf("%s.%s has more than two input parameters", m.Type.In(0).String(), m.Name)
+		}
+		if in := m.Type.In(i); engine.provider(in) == nil {
+			return fmt.Errorf("no provider of %s for the argument %d of %s.%s", in, i, m.Type.In(0).String(), m.Name)
+		}
+	}
+	return nil
+}
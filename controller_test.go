@@ -0,0 +1,141 @@
+package rester
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type RespondCtl struct {
+	BaseCtl
+}
+
+func (ctl *RespondCtl) GET(args struct {
+	Status string `query:"status"`
+}) {
+	switch args.Status {
+	case "bad":
+		ctl.BadRequest(1, "bad")
+	case "created":
+		ctl.Created("/x/1", H{"id": 1})
+	case "accepted":
+		ctl.Accepted(H{"id": 1})
+	case "none":
+		ctl.NoContent()
+	case "abort":
+		ctl.Abort(&CodeMsg{Code: 403, Msg: "denied"})
+	default:
+		ctl.OK(H{"id": 1})
+	}
+}
+
+func serveTest(handlers map[string]RequestHandler, method, uri string) *fasthttp.RequestCtx {
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(uri)
+	handlers[method](ctx)
+	return ctx
+}
+
+func TestRespond(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	handlers := MustNewHandlers(new(RespondCtl))
+
+	var cases = []struct {
+		query    string
+		status   int
+		body     string
+		location string
+	}{
+		{"", 200, `{"id":1}`, ""},
+		{"bad", 400, `{"code":1,"msg":"bad"}`, ""},
+		{"created", 201, `{"id":1}`, "/x/1"},
+		{"accepted", 202, `{"id":1}`, ""},
+		{"none", 204, "", ""},
+		{"abort", 403, `{"code":403,"msg":"denied"}`, ""},
+	}
+	for _, c := range cases {
+		ctx := serveTest(handlers, "GET", "/?status="+c.query)
+		assert.Equal(t, c.status, ctx.Response.StatusCode(), c.query)
+		assert.Equal(t, c.body, string(ctx.Response.Body()), c.query)
+		assert.Equal(t, c.location, string(ctx.Response.Header.Peek("Location")), c.query)
+	}
+}
+
+type DenyMwCtl struct {
+	BaseCtl
+}
+
+func (ctl *DenyMwCtl) Any() {
+	ctl.Unauthorized(401, "who are you")
+}
+
+type DeniedCtl struct {
+	DenyMwCtl
+}
+
+func (ctl *DeniedCtl) GET() {
+	ctl.OK(H{"secret": 1})
+}
+
+func TestMiddlewareAbort(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	ctx := serveTest(MustNewHandlers(new(DeniedCtl)), "GET", "/")
+	assert.Equal(t, 401, ctx.Response.StatusCode())
+	assert.Equal(t, `{"code":401,"msg":"who are you"}`, string(ctx.Response.Body()))
+}
+
+type errNotFound string
+
+func (e errNotFound) Error() string { return string(e) }
+
+type ResultCtl struct {
+	BaseCtl
+}
+
+func (ctl *ResultCtl) GET(args struct {
+	ID int `query:"id"`
+}) (H, error) {
+	switch args.ID {
+	case 0:
+		return nil, errNotFound("no such user")
+	case 1:
+		return nil, &CodeMsg{Code: 409, Msg: "conflict"}
+	case 2:
+		return nil, nil
+	}
+	return H{"id": args.ID}, nil
+}
+
+func TestResult(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	engine := New()
+	engine.ErrorMapper = func(err error) (int, interface{}) {
+		if _, ok := err.(errNotFound); ok {
+			return 404, nil
+		}
+		return 0, nil
+	}
+	handlers, _, err := newHandlers(new(ResultCtl), nil, handlerOptions{engine: engine})
+	assert.NoError(t, err)
+
+	var cases = []struct {
+		query  string
+		status int
+		body   string
+	}{
+		{"id=0", 404, `{"code":404,"msg":"no such user"}`},
+		{"id=1", 409, `{"code":409,"msg":"conflict"}`},
+		{"id=2", 200, ""},
+		{"id=3", 200, `{"id":3}`},
+	}
+	for _, c := range cases {
+		ctx := serveTest(handlers, "GET", "/?"+c.query)
+		assert.Equal(t, c.status, ctx.Response.StatusCode(), c.query)
+		assert.Equal(t, c.body, string(ctx.Response.Body()), c.query)
+	}
+}
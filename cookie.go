@@ -0,0 +1,28 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+import (
+	"github.com/henrylee2cn/rester/binding"
+)
+
+// Keyring returns the keyring of the engine, which signs or encrypts the cookies of the response structs,
+// and verifies or decrypts the cookies of the arguments, e.g. `cookie:"session,signed"`.
+// NOTE:
+//  It has no key until the keys are set, e.g. engine.Keyring().SetKeys(newKey, oldKey);
+//  The own binding of the engine uses it, but the binding set by Router.SetBinding uses its own keyring.
+func (engine *Engine) Keyring() *binding.Keyring {
+	return engine.keyring
+}
@@ -0,0 +1,70 @@
+package rester
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	SessionCtl struct {
+		BaseCtl
+	}
+	sessionResult struct {
+		Status  int    `status:""`
+		Session string `cookie:"session,signed"`
+	}
+)
+
+func (ctl *SessionCtl) POST() (*sessionResult, error) {
+	return &sessionResult{Status: 204, Session: "user=1"}, nil
+}
+
+func (ctl *SessionCtl) GET(args struct {
+	Session string `cookie:"session,signed,required"`
+}) (H, error) {
+	return H{"session": args.Session}, nil
+}
+
+func TestKeyring(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	engine := New()
+	engine.Keyring().SetKeys([]byte("secret"))
+	handlers, _, err := newHandlers(new(SessionCtl), nil, handlerOptions{engine: engine})
+	assert.NoError(t, err)
+
+	ctx := new(RequestCtx)
+	ctx.Init(new(fasthttp.Request), nil, nil)
+	handlers["POST"](ctx)
+	assert.Equal(t, 204, ctx.Response.StatusCode())
+	var ck fasthttp.Cookie
+	assert.NoError(t, ck.ParseBytes(ctx.Response.Header.PeekCookie("session")))
+	assert.NotEqual(t, "user=1", string(ck.Value()))
+
+	serve := func(session []byte) *RequestCtx {
+		ctx := new(RequestCtx)
+		ctx.Init(new(fasthttp.Request), nil, nil)
+		ctx.Request.Header.SetCookieBytesKV([]byte("session"), session)
+		handlers["GET"](ctx)
+		return ctx
+	}
+	ctx = serve(ck.Value())
+	assert.Equal(t, 200, ctx.Response.StatusCode())
+	assert.Equal(t, `{"session":"user=1"}`, string(ctx.Response.Body()))
+
+	ctx = serve([]byte("user=1"))
+	assert.Equal(t, 400, ctx.Response.StatusCode())
+
+	// the binding set by the caller is not changed
+	b := NewBinding()
+	handlers, _, err = newHandlers(new(SessionCtl), nil, handlerOptions{engine: engine, binding: b})
+	assert.NoError(t, err)
+	assert.Nil(t, b.Keyring())
+	ctx = serve(ck.Value())
+	assert.Equal(t, 400, ctx.Response.StatusCode())
+	b.SetKeyring(engine.Keyring())
+	ctx = serve(ck.Value())
+	assert.Equal(t, 200, ctx.Response.StatusCode())
+}
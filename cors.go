@@ -0,0 +1,188 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrylee2cn/ameda"
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// CORSConfig the Cross-Origin Resource Sharing policy of the CORS_* methods
+	CORSConfig struct {
+		// AllowOrigins the allowed origins, supports:
+		//  exact origin, e.g. 'https://example.com';
+		//  wildcard subdomain, e.g. 'https://*.example.com';
+		//  '*' allows any origin, which cannot be used with AllowCredentials.
+		AllowOrigins []string
+		// AllowOriginPatterns the regular expressions that match the allowed origins,
+		// e.g. '^https://[a-z0-9-]+\.example\.com$'
+		AllowOriginPatterns []string
+		// AllowHeaders the allowed request headers,
+		// the headers of 'Access-Control-Request-Headers' are allowed when empty.
+		AllowHeaders []string
+		// ExposeHeaders the response headers that clients are allowed to access
+		ExposeHeaders []string
+		// AllowCredentials whether the response can be shared when the request's credentials mode is 'include'
+		AllowCredentials bool
+		// MaxAge how long the results of a preflight request can be cached, not sent when zero
+		MaxAge time.Duration
+	}
+	// CORSProvider optional interface of Controller,
+	// provides the CORS policy of its CORS_* methods.
+	CORSProvider interface {
+		CORSConfig() *CORSConfig
+	}
+	corsPolicy struct {
+		allowAll      bool
+		origins       map[string]bool
+		wildcards     [][2]string // {prefix,suffix}
+		patterns      []*regexp.Regexp
+		allowMethods  string
+		allowHeaders  string
+		exposeHeaders string
+		credentials   bool
+		maxAge        string
+	}
+)
+
+// defaultCORSConfig allows any origin without credentials.
+var defaultCORSConfig = &CORSConfig{AllowOrigins: []string{"*"}}
+
+func newCorsPolicy(corsMethods map[string]struct{}, config *CORSConfig) (*corsPolicy, error) {
+	if config == nil {
+		config = defaultCORSConfig
+	}
+	var a []string
+	for _, m := range httpMethodList {
+		if _, ok := corsMethods[m]; ok {
+			a = append(a, m)
+		}
+	}
+	p := &corsPolicy{
+		origins:       make(map[string]bool, len(config.AllowOrigins)),
+		allowMethods:  strings.Join(a, ", "),
+		allowHeaders:  strings.Join(config.AllowHeaders, ", "),
+		exposeHeaders: strings.Join(config.ExposeHeaders, ", "),
+		credentials:   config.AllowCredentials,
+	}
+	if config.MaxAge > 0 {
+		p.maxAge = strconv.FormatInt(int64(config.MaxAge/time.Second), 10)
+	}
+	for _, origin := range config.AllowOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch idx := strings.Index(origin, "*"); {
+		case origin == "*":
+			p.allowAll = true
+		case idx == -1:
+			p.origins[origin] = true
+		case strings.HasSuffix(origin[:idx], "://") && strings.HasPrefix(origin[idx+1:], ".") && !strings.Contains(origin[idx+1:], "*"):
+			p.wildcards = append(p.wildcards, [2]string{origin[:idx], origin[idx+1:]})
+		default:
+			return nil, fmt.Errorf("invalid CORS wildcard origin %q, expect the form 'https://*.example.com'", origin)
+		}
+	}
+	for _, pattern := range config.AllowOriginPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS origin pattern %q: %s", pattern, err)
+		}
+		p.patterns = append(p.patterns, re)
+	}
+	// the fetch standard forbids sharing the credentialed response with any origin
+	if p.allowAll && p.credentials {
+		return nil, errors.New("the CORS origin '*' cannot be used with AllowCredentials")
+	}
+	return p, nil
+}
+
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+	for _, w := range p.wildcards {
+		if len(origin) > len(w[0])+len(w[1]) &&
+			strings.HasPrefix(origin, w[0]) && strings.HasSuffix(origin, w[1]) &&
+			!strings.ContainsAny(origin[len(w[0]):len(origin)-len(w[1])], "/:") {
+			return true
+		}
+	}
+	for _, re := range p.patterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func newCorsFunc(corsMethods map[string]struct{}, config *CORSConfig) (RequestHandler, error) {
+	p, err := newCorsPolicy(corsMethods, config)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *RequestCtx) {
+		preflight := c.Request.Header.IsOptions()
+		if preflight {
+			defer func() {
+				c.SetStatusCode(fasthttp.StatusNoContent)
+				c.SetBodyString("")
+			}()
+		}
+		// the response varies by origin unless any origin is allowed
+		if !p.allowAll {
+			c.Response.Header.Add(fasthttp.HeaderVary, "Origin")
+		}
+		origin := ameda.UnsafeBytesToString(c.Request.Header.Peek("Origin"))
+		if origin == "" || !p.allowOrigin(origin) {
+			return
+		}
+		h := &c.Response.Header
+		if p.allowAll {
+			h.Set(fasthttp.HeaderAccessControlAllowOrigin, "*")
+		} else {
+			h.Set(fasthttp.HeaderAccessControlAllowOrigin, origin)
+		}
+		if p.credentials {
+			h.Set(fasthttp.HeaderAccessControlAllowCredentials, "true")
+		}
+		if !preflight {
+			if p.exposeHeaders != "" {
+				h.Set(fasthttp.HeaderAccessControlExposeHeaders, p.exposeHeaders)
+			}
+			return
+		}
+		h.Set(fasthttp.HeaderAccessControlAllowMethods, p.allowMethods)
+		if p.allowHeaders != "" {
+			h.Set(fasthttp.HeaderAccessControlAllowHeaders, p.allowHeaders)
+		} else if reqHeaders := c.Request.Header.Peek(fasthttp.HeaderAccessControlRequestHeaders); len(reqHeaders) > 0 {
+			h.SetBytesV(fasthttp.HeaderAccessControlAllowHeaders, reqHeaders)
+			h.Add(fasthttp.HeaderVary, fasthttp.HeaderAccessControlRequestHeaders)
+		}
+		if p.maxAge != "" {
+			h.Set(fasthttp.HeaderAccessControlMaxAge, p.maxAge)
+		}
+	}, nil
+}
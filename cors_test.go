@@ -0,0 +1,95 @@
+package rester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type CorsTestCtl struct {
+	BaseCtl
+}
+
+func (ctl *CorsTestCtl) CORS_GET() {}
+
+type CorsConfigCtl struct {
+	BaseCtl
+}
+
+func (ctl *CorsConfigCtl) CORS_GET() {}
+
+func (*CorsConfigCtl) CORSConfig() *CORSConfig {
+	return &CORSConfig{AllowOrigins: []string{"https://app.example.com"}}
+}
+
+func serveCORS(r *Router, method, path, origin string) *fasthttp.RequestCtx {
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(path)
+	if origin != "" {
+		ctx.Request.Header.Set("Origin", origin)
+	}
+	if method == "OPTIONS" {
+		ctx.Request.Header.Set("Access-Control-Request-Method", "GET")
+		ctx.Request.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	}
+	r.router.Handler(ctx)
+	return ctx
+}
+
+func TestCORS(t *testing.T) {
+	var r Router
+	r.DefControl("/default", new(CorsTestCtl))
+	g := r.Group("/g")
+	g.SetCORS(&CORSConfig{
+		AllowOrigins:        []string{"https://example.com", "https://*.example.org"},
+		AllowOriginPatterns: []string{`^http://localhost:\d+$`},
+		AllowHeaders:        []string{"X-Token"},
+		ExposeHeaders:       []string{"X-Request-Id"},
+		AllowCredentials:    true,
+		MaxAge:              time.Hour,
+	})
+	g.DefControl("/strict", new(CorsTestCtl))
+	g.DefControl("/ctl", new(CorsConfigCtl))
+
+	ctx := serveCORS(&r, "GET", "/default", "https://any.com")
+	assert.Equal(t, "*", string(ctx.Response.Header.Peek("Access-Control-Allow-Origin")))
+	assert.Empty(t, ctx.Response.Header.Peek("Access-Control-Allow-Credentials"))
+	ctx = serveCORS(&r, "OPTIONS", "/default", "https://any.com")
+	assert.Equal(t, 204, ctx.Response.StatusCode())
+	assert.Equal(t, "X-Custom", string(ctx.Response.Header.Peek("Access-Control-Allow-Headers")))
+
+	for _, origin := range []string{"https://example.com", "https://a.b.example.org", "http://localhost:8080"} {
+		ctx = serveCORS(&r, "GET", "/g/strict", origin)
+		assert.Equal(t, origin, string(ctx.Response.Header.Peek("Access-Control-Allow-Origin")))
+		assert.Equal(t, "true", string(ctx.Response.Header.Peek("Access-Control-Allow-Credentials")))
+		assert.Equal(t, "X-Request-Id", string(ctx.Response.Header.Peek("Access-Control-Expose-Headers")))
+		assert.Equal(t, "Origin", string(ctx.Response.Header.Peek("Vary")))
+	}
+	ctx = serveCORS(&r, "OPTIONS", "/g/strict", "https://example.com")
+	assert.Equal(t, "GET", string(ctx.Response.Header.Peek("Access-Control-Allow-Methods")))
+	assert.Equal(t, "X-Token", string(ctx.Response.Header.Peek("Access-Control-Allow-Headers")))
+	assert.Equal(t, "3600", string(ctx.Response.Header.Peek("Access-Control-Max-Age")))
+
+	for _, origin := range []string{"https://evil.com", "https://example.org", "https://example.com.evil.com", "http://example.com"} {
+		ctx = serveCORS(&r, "OPTIONS", "/g/strict", origin)
+		assert.Equal(t, 204, ctx.Response.StatusCode())
+		assert.Empty(t, ctx.Response.Header.Peek("Access-Control-Allow-Origin"), origin)
+		assert.Empty(t, ctx.Response.Header.Peek("Access-Control-Allow-Methods"), origin)
+		assert.Empty(t, ctx.Response.Header.Peek("Access-Control-Allow-Credentials"), origin)
+	}
+
+	ctx = serveCORS(&r, "GET", "/g/ctl", "https://example.com")
+	assert.Empty(t, ctx.Response.Header.Peek("Access-Control-Allow-Origin"))
+	ctx = serveCORS(&r, "GET", "/g/ctl", "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", string(ctx.Response.Header.Peek("Access-Control-Allow-Origin")))
+
+	// any origin with credentials
+	_, err := newCorsFunc(map[string]struct{}{"GET": {}}, &CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	assert.EqualError(t, err, "the CORS origin '*' cannot be used with AllowCredentials")
+	g = r.Group("/any")
+	g.SetCORS(&CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	assert.Panics(t, func() { g.DefControl("/ctl", new(CorsTestCtl)) })
+}
@@ -1,6 +1,10 @@
 package main
 
-import "github.com/henrylee2cn/rester"
+import (
+	"time"
+
+	"github.com/henrylee2cn/rester"
+)
 
 type CorsCtl struct {
 	rester.BaseCtl
@@ -13,6 +17,11 @@ func (ctl *CorsCtl) CORS_GET() {
 
 func main() {
 	engine := rester.New()
+	engine.SetCORS(&rester.CORSConfig{
+		AllowOrigins:     []string{"http://localhost:3000", "https://*.example.com"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	})
 	engine.DefControl("/", new(CorsCtl))
 	err := engine.ListenAndServe(":8080")
 	if err != nil {
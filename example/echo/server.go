@@ -21,11 +21,12 @@ type EchoCtl struct {
 }
 
 func (ctl *EchoCtl) GET(args struct {
+	A *string  `ctx:"a"`
 	B []string `query:"b"`
 }) {
 	ctl.Logger().Printf("EchoCtl: b=%v", args.B)
 	ctl.OK(rester.H{
-		"a": ctl.UserValue("a"),
+		"a": args.A,
 		"b": args.B,
 	})
 }
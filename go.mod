@@ -8,7 +8,6 @@ require (
 	github.com/bytedance/json v0.0.0-20190516032711-0d89175f1949
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/gogo/protobuf v1.2.1
-	github.com/golang/protobuf v1.3.3 // indirect
 	github.com/henrylee2cn/ameda v1.4.1-0.20200623095842-ee42446e0062
 	github.com/henrylee2cn/goutil v0.0.0-20200623104149-bd46b98d2fd9
 	github.com/kr/pretty v0.1.0 // indirect
@@ -16,6 +15,7 @@ require (
 	github.com/stretchr/testify v1.4.0
 	github.com/tidwall/gjson v1.6.0
 	github.com/valyala/fasthttp v1.14.0
+	github.com/vmihailenco/msgpack/v4 v4.3.12
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 	gopkg.in/yaml.v2 v2.2.8 // indirect
 )
@@ -0,0 +1,165 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Scope the lifetime of the value created by the provider
+type Scope uint8
+
+const (
+	// Singleton the value is created on the first injection and shared by all requests,
+	// and it is cleaned up when the engine shuts down.
+	Singleton Scope = iota
+	// PerRequest the value is created once per request,
+	// and it is cleaned up when the method chain finishes.
+	PerRequest
+	// Factory the value is created on every injection,
+	// and it is cleaned up when the method chain finishes.
+	Factory
+)
+
+// Provider creates the value of the injected argument,
+// the cleanup function can be nil.
+type Provider func(ctx *RequestCtx) (value interface{}, cleanup func(), err error)
+
+type provider struct {
+	typ     reflect.Type
+	scope   Scope
+	provide Provider
+
+	mu      sync.Mutex
+	created bool
+	value   reflect.Value
+	cleanup func()
+}
+
+// Provide registers the provider of the type, the controller methods can declare the arguments
+// of the type after the request argument, e.g. 'func (c *UserCtl) GET(args Req, repo *UserRepo)'.
+// NOTE:
+//  It must be called before any controller is registered,
+//  so that the providers are not changed while the requests are handled;
+//  The first argument is bound from the request if its type is not provided;
+//  panic if the provider is nil or any controller has been registered.
+func (engine *Engine) Provide(typ reflect.Type, scope Scope, provide Provider) {
+	if provide == nil {
+		panic("rester: provider cannot be nil")
+	}
+	if len(engine.Router.controllerNames) > 0 {
+		panic("rester: Provide must be called before any controller is registered")
+	}
+	if engine.providers == nil {
+		engine.providers = make(map[reflect.Type]*provider)
+	}
+	engine.providers[typ] = &provider{typ: typ, scope: scope, provide: provide}
+}
+
+// ProvideValue registers the singleton value, whose type is the dynamic type of the value.
+func (engine *Engine) ProvideValue(value interface{}) {
+	engine.Provide(reflect.TypeOf(value), Singleton, func(*RequestCtx) (interface{}, func(), error) {
+		return value, nil, nil
+	})
+}
+
+func (engine *Engine) provider(typ reflect.Type) *provider {
+	if engine == nil {
+		return nil
+	}
+	return engine.providers[typ]
+}
+
+// cleanupSingletons cleans up the created singleton values.
+func (engine *Engine) cleanupSingletons() {
+	for _, p := range engine.providers {
+		p.mu.Lock()
+		if p.created && p.cleanup != nil {
+			p.cleanup()
+		}
+		p.created, p.value, p.cleanup = false, reflect.Value{}, nil
+		p.mu.Unlock()
+	}
+}
+
+func (p *provider) call(ctx *RequestCtx) (reflect.Value, func(), error) {
+	value, cleanup, err := p.provide(ctx)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		v = reflect.Zero(p.typ)
+	} else if !v.Type().AssignableTo(p.typ) {
+		if cleanup != nil {
+			cleanup()
+		}
+		return reflect.Value{}, nil, fmt.Errorf("provider of %s returns %s", p.typ, v.Type())
+	}
+	return v, cleanup, nil
+}
+
+func (p *provider) singleton(ctx *RequestCtx) (reflect.Value, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.created {
+		v, cleanup, err := p.call(ctx)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p.created, p.value, p.cleanup = true, v, cleanup
+	}
+	return p.value, nil
+}
+
+// requestScope the values injected in a request
+type requestScope struct {
+	values   map[reflect.Type]reflect.Value
+	cleanups []func()
+}
+
+func (s *requestScope) inject(p *provider, ctx *RequestCtx) (reflect.Value, error) {
+	switch p.scope {
+	case Singleton:
+		return p.singleton(ctx)
+	case PerRequest:
+		if v, ok := s.values[p.typ]; ok {
+			return v, nil
+		}
+	}
+	v, cleanup, err := p.call(ctx)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if cleanup != nil {
+		s.cleanups = append(s.cleanups, cleanup)
+	}
+	if p.scope == PerRequest {
+		if s.values == nil {
+			s.values = make(map[reflect.Type]reflect.Value, 4)
+		}
+		s.values[p.typ] = v
+	}
+	return v, nil
+}
+
+// cleanup calls the cleanup functions in reverse order of creation.
+func (s *requestScope) cleanup() {
+	for i := len(s.cleanups) - 1; i >= 0; i-- {
+		s.cleanups[i]()
+	}
+}
@@ -0,0 +1,80 @@
+package rester
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type injectRepo struct{ id int }
+
+type injectTx struct{ id int }
+
+type injectLogger struct{ prefix string }
+
+type InjectCtl struct {
+	BaseCtl
+}
+
+func (ctl *InjectCtl) GET(args struct {
+	A string `query:"a"`
+}, repo *injectRepo, tx *injectTx, tx2 *injectTx, logger *injectLogger) (H, error) {
+	return H{"a": args.A, "repo": repo.id, "tx": tx.id, "same": tx == tx2, "log": logger.prefix}, nil
+}
+
+type InjectOnlyCtl struct {
+	BaseCtl
+}
+
+func (ctl *InjectOnlyCtl) GET(repo *injectRepo) {}
+
+func (ctl *InjectOnlyCtl) POST(args struct{}, unknown *injectLogger) {}
+
+func TestInject(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	engine := New()
+	var repos, txs, cleanups []int
+	engine.Provide(reflect.TypeOf(new(injectRepo)), Singleton, func(*RequestCtx) (interface{}, func(), error) {
+		repos = append(repos, len(repos)+1)
+		return &injectRepo{id: len(repos)}, func() { cleanups = append(cleanups, -1) }, nil
+	})
+	engine.Provide(reflect.TypeOf(new(injectTx)), PerRequest, func(*RequestCtx) (interface{}, func(), error) {
+		txs = append(txs, len(txs)+1)
+		id := len(txs)
+		return &injectTx{id: id}, func() { cleanups = append(cleanups, id) }, nil
+	})
+	engine.ProvideValue(&injectLogger{prefix: "log"})
+	handlers, _, err := newHandlers(new(InjectCtl), nil, handlerOptions{engine: engine})
+	assert.NoError(t, err)
+
+	ctx := serveTest(handlers, "GET", "/?a=x")
+	assert.Equal(t, `{"a":"x","log":"log","repo":1,"same":true,"tx":1}`, string(ctx.Response.Body()))
+	ctx = serveTest(handlers, "GET", "/?a=y")
+	assert.Equal(t, `{"a":"y","log":"log","repo":1,"same":true,"tx":2}`, string(ctx.Response.Body()))
+	assert.Equal(t, []int{1, 2}, cleanups)
+	engine.cleanupSingletons()
+	assert.Equal(t, []int{1, 2, -1}, cleanups)
+
+	engine.Provide(reflect.TypeOf(new(injectTx)), Factory, func(*RequestCtx) (interface{}, func(), error) {
+		return nil, nil, errors.New("no database")
+	})
+	engine.ErrorMapper = func(err error) (int, interface{}) { return 503, nil }
+	ctx = serveTest(handlers, "GET", "/")
+	assert.Equal(t, 503, ctx.Response.StatusCode())
+	assert.Equal(t, `{"code":503,"msg":"no database"}`, string(ctx.Response.Body()))
+
+	_, _, err = newHandlers(new(InjectOnlyCtl), nil, handlerOptions{engine: New()})
+	assert.EqualError(t, err, "no provider of *rester.injectLogger for the argument 2 of *rester.InjectOnlyCtl.POST")
+	_, err = NewHandlers(new(InjectCtl))
+	assert.EqualError(t, err, "*rester.InjectCtl.GET has more than two input parameters")
+	assert.Panics(t, func() { New().DefControl("/inject", new(InjectOnlyCtl)) })
+
+	engine = New()
+	engine.DefControl("/render", new(RenderCtl))
+	assert.PanicsWithValue(t, "rester: Provide must be called before any controller is registered", func() {
+		engine.ProvideValue(&injectLogger{})
+	})
+}
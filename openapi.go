@@ -0,0 +1,288 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+import (
+	jsonpkg "encoding/json"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/json"
+	"github.com/henrylee2cn/ameda"
+
+	"github.com/henrylee2cn/rester/binding"
+	"github.com/henrylee2cn/rester/openapi"
+)
+
+// OpenAPI returns the OpenAPI 3.1 document generated from the registered controllers.
+// NOTE:
+//  The parameters come from the argument structs of the chain methods,
+//  and the response comes from the result type of the controller's own method.
+func (engine *Engine) OpenAPI() *openapi.Document {
+	info := engine.OpenAPIInfo
+	if info.Title == "" {
+		info.Title = engine.Name
+		if info.Title == "" {
+			info.Title = "rester"
+		}
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info:    info,
+		Paths:   make(map[string]openapi.PathItem),
+	}
+	g := openapi.NewGenerator()
+	// operationIDs {operationId:count}, the repeated ID of the controller mounted on several paths is suffixed by the count
+	operationIDs := make(map[string]int)
+	for _, rt := range engine.Router.routeList() {
+		path := openAPIPath(rt.path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(openapi.PathItem)
+			doc.Paths[path] = item
+		}
+		op := newOperation(engine, g, rt, path)
+		n := operationIDs[op.OperationID] + 1
+		operationIDs[op.OperationID] = n
+		if n > 1 {
+			op.OperationID += "_" + strconv.Itoa(n)
+		}
+		item[strings.ToLower(rt.httpMethod)] = op
+	}
+	if schemas := g.Components(); len(schemas) > 0 {
+		doc.Components = &openapi.Components{Schemas: schemas}
+	}
+	return doc
+}
+
+func (engine *Engine) serveOpenAPI(ctx *RequestCtx) {
+	engine.openAPILock.Lock()
+	if engine.openAPIBody == nil {
+		engine.openAPIBody, _ = json.Marshal(engine.OpenAPI())
+	}
+	body := engine.openAPIBody
+	engine.openAPILock.Unlock()
+	ctx.SetContentType(jsonContentType)
+	ctx.SetBody(body)
+}
+
+// resetOpenAPI clears the cached document, which is generated again on the next request.
+func (engine *Engine) resetOpenAPI() {
+	engine.openAPILock.Lock()
+	engine.openAPIBody = nil
+	engine.openAPILock.Unlock()
+}
+
+// openAPIPath converts the route path to OpenAPI path template, e.g. '/user/:id' to '/user/{id}'.
+func openAPIPath(path string) string {
+	a := strings.Split(path, "/")
+	for i, s := range a {
+		if len(s) > 1 && (s[0] == ':' || s[0] == '*') {
+			a[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(a, "/")
+}
+
+var codeMsgType = reflect.TypeOf(CodeMsg{})
+
+func newOperation(engine *Engine, g *openapi.Generator, rt *route, path string) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: rt.controller.Name() + "_" + rt.httpMethod,
+		Responses:   make(map[string]*openapi.Response, 2),
+	}
+	withBody := methodWithBody(rt.httpMethod)
+	for _, m := range rt.methods {
+		if m.Type.NumIn() > 1 && engine.provider(m.Type.In(1)) == nil {
+			addOperationArgs(g, op, rt.binding, m.Type.In(1), withBody)
+		}
+	}
+	for _, s := range strings.Split(path, "/") {
+		if len(s) > 2 && s[0] == '{' {
+			addOperationParam(op, &openapi.Parameter{
+				Name:     s[1 : len(s)-1],
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+	}
+	handler := rt.methods[len(rt.methods)-1]
+	ok := &openapi.Response{Description: "OK"}
+	if handler.Type.NumOut() == 2 {
+		resultType := handler.Type.Out(0)
+		if bodyType, isResponse, _ := binding.ResponseBodyType(resultType); isResponse {
+			resultType = bodyType
+		}
+		if resultType != nil {
+			ok.Content = jsonMediaTypes(g.Schema(resultType))
+		}
+	}
+	op.Responses["200"] = ok
+	op.Responses["default"] = &openapi.Response{
+		Description: "Error",
+		Content:     jsonMediaTypes(g.Schema(codeMsgType)),
+	}
+	return op
+}
+
+func methodWithBody(httpMethod string) bool {
+	switch httpMethod {
+	case "GET", "HEAD", "DELETE", "OPTIONS", "TRACE", "CONNECT":
+		return false
+	default:
+		return true
+	}
+}
+
+func jsonMediaTypes(schema *openapi.Schema) map[string]*openapi.MediaType {
+	return map[string]*openapi.MediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+func addOperationArgs(g *openapi.Generator, op *openapi.Operation, b *binding.Binding, argType reflect.Type, withBody bool) {
+	t := ameda.DereferenceType(argType)
+	if t.Kind() != reflect.Struct {
+		if withBody {
+			setRequestBody(op, "application/json", g.Schema(t))
+		}
+		return
+	}
+	params, err := b.Params(t)
+	if err != nil {
+		return
+	}
+	var jsonBody, xmlBody, formBody *openapi.Schema
+	var withFile bool
+	for _, p := range params {
+		if p.Implicit && p.In != "json" && p.In != "query" {
+			continue
+		}
+		if p.Implicit && (p.In == "json") != withBody {
+			continue
+		}
+		schema := g.FieldSchema(p.Field)
+		if p.Default != "" {
+			var v interface{}
+			if jsonpkg.Unmarshal([]byte(p.Default), &v) == nil {
+				schema.Default = v
+			} else {
+				schema.Default = p.Default
+			}
+		}
+		switch p.In {
+		case "path", "query", "header", "cookie":
+			param := &openapi.Parameter{
+				Name:     p.Name,
+				In:       p.In,
+				Required: p.Required || p.In == "path",
+				Style:    p.Style,
+				Schema:   schema,
+			}
+			if p.Style != "" {
+				// deepObject is exploded, and the delimited arrays are not
+				explode := p.Style == "deepObject"
+				param.Explode = &explode
+			}
+			addOperationParam(op, param)
+		case "json":
+			if !withBody || strings.Contains(p.FieldSelector, ".") {
+				continue
+			}
+			jsonBody = addBodyProperty(op, jsonBody, "application/json", p.Name, p.Required, schema)
+		case "xml":
+			if !withBody || strings.Contains(p.FieldSelector, ".") {
+				continue
+			}
+			xmlBody = addBodyProperty(op, xmlBody, "application/xml", p.Name, p.Required, schema)
+		case "form":
+			if !withBody {
+				continue
+			}
+			if s := fileSchema(p.Field.Type); s != nil {
+				schema, withFile = s, true
+			}
+			formBody = addBodyProperty(op, formBody, "application/x-www-form-urlencoded", p.Name, p.Required, schema)
+			setRequestBody(op, "multipart/form-data", formBody)
+			if withFile {
+				delete(op.RequestBody.Content, "application/x-www-form-urlencoded")
+			}
+		case "protobuf", "raw_body":
+			if !withBody {
+				continue
+			}
+			mediaType := "application/octet-stream"
+			if p.In == "protobuf" {
+				mediaType = "application/x-protobuf"
+			}
+			setRequestBody(op, mediaType, &openapi.Schema{Type: "string", Format: "binary"})
+			if p.Required {
+				op.RequestBody.Required = true
+			}
+		}
+	}
+}
+
+func addOperationParam(op *openapi.Operation, param *openapi.Parameter) {
+	for _, p := range op.Parameters {
+		if p.In == param.In && p.Name == param.Name {
+			return
+		}
+	}
+	op.Parameters = append(op.Parameters, param)
+}
+
+var (
+	fileHeaderType = reflect.TypeOf(new(multipart.FileHeader))
+	fileType       = reflect.TypeOf(new(binding.File))
+)
+
+// fileSchema returns the schema of the uploaded file type, or nil if it is not.
+func fileSchema(t reflect.Type) *openapi.Schema {
+	switch t {
+	case fileHeaderType, fileType:
+		return &openapi.Schema{Type: "string", Format: "binary"}
+	case reflect.SliceOf(fileHeaderType), reflect.SliceOf(fileType):
+		return &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string", Format: "binary"}}
+	default:
+		return nil
+	}
+}
+
+func addBodyProperty(op *openapi.Operation, body *openapi.Schema, mediaType, name string, required bool, schema *openapi.Schema) *openapi.Schema {
+	if body == nil {
+		body = &openapi.Schema{Type: "object", Properties: make(map[string]*openapi.Schema)}
+		setRequestBody(op, mediaType, body)
+	}
+	body.Properties[name] = schema
+	if required {
+		body.Required = append(body.Required, name)
+		op.RequestBody.Required = true
+	}
+	return body
+}
+
+func setRequestBody(op *openapi.Operation, mediaType string, schema *openapi.Schema) {
+	if op.RequestBody == nil {
+		op.RequestBody = &openapi.RequestBody{Content: make(map[string]*openapi.MediaType, 1)}
+	}
+	op.RequestBody.Content[mediaType] = &openapi.MediaType{Schema: schema}
+}
@@ -0,0 +1,100 @@
+// Package openapi the OpenAPI 3.1 document model and JSON schema generator.
+//
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package openapi
+
+// Version the version of OpenAPI specification
+const Version = "3.1.0"
+
+type (
+	// Document the root object of the OpenAPI document
+	Document struct {
+		OpenAPI    string              `json:"openapi"`
+		Info       Info                `json:"info"`
+		Paths      map[string]PathItem `json:"paths"`
+		Components *Components         `json:"components,omitempty"`
+	}
+	// Info the metadata about the API
+	Info struct {
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+		Version     string `json:"version"`
+	}
+	// PathItem the operations available on a single path, {lowerCaseMethod:Operation}
+	PathItem map[string]*Operation
+	// Operation a single API operation on a path
+	Operation struct {
+		OperationID string               `json:"operationId,omitempty"`
+		Summary     string               `json:"summary,omitempty"`
+		Tags        []string             `json:"tags,omitempty"`
+		Parameters  []*Parameter         `json:"parameters,omitempty"`
+		RequestBody *RequestBody         `json:"requestBody,omitempty"`
+		Responses   map[string]*Response `json:"responses"`
+	}
+	// Parameter a single operation parameter
+	Parameter struct {
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description,omitempty"`
+		Required    bool    `json:"required,omitempty"`
+		Style       string  `json:"style,omitempty"`
+		Explode     *bool   `json:"explode,omitempty"`
+		Schema      *Schema `json:"schema,omitempty"`
+	}
+	// RequestBody the request body of operation
+	RequestBody struct {
+		Description string                `json:"description,omitempty"`
+		Required    bool                  `json:"required,omitempty"`
+		Content     map[string]*MediaType `json:"content"`
+	}
+	// MediaType the schema of a media type
+	MediaType struct {
+		Schema *Schema `json:"schema,omitempty"`
+	}
+	// Response a single response of operation
+	Response struct {
+		Description string                `json:"description"`
+		Content     map[string]*MediaType `json:"content,omitempty"`
+	}
+	// Components the reusable objects
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas,omitempty"`
+	}
+)
+
+// Schema the JSON schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	// the constraints converted from the validator expression
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	// Validator the validator expression of the field, such as `vd:"$>0"`
+	Validator string `json:"x-validator,omitempty"`
+}
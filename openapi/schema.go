@@ -0,0 +1,265 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generator generates the JSON schemas of Go types,
+// and collects the named struct types into the component schemas.
+type Generator struct {
+	// ValidatorTag the validator tag name, use 'vd' by default when empty
+	ValidatorTag string
+	schemas      map[string]*Schema
+	names        map[reflect.Type]string
+}
+
+// NewGenerator creates a schema generator.
+func NewGenerator() *Generator {
+	return &Generator{
+		ValidatorTag: "vd",
+		schemas:      make(map[string]*Schema),
+		names:        make(map[reflect.Type]string),
+	}
+}
+
+// Components returns the component schemas collected so far.
+func (g *Generator) Components() map[string]*Schema {
+	return g.schemas
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// Schema returns the JSON schema of the type,
+// the named struct type is referenced to the component schema.
+func (g *Generator) Schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case bytesType:
+		return &Schema{Type: "string", Format: "byte"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.Schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.Schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.componentName(t)}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *Generator) componentName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, ok := g.schemas[name]; ok {
+		name = strings.Replace(t.String(), ".", "_", -1)
+	}
+	g.names[t] = name
+	g.schemas[name] = nil // placeholder for recursive types
+	g.schemas[name] = g.structSchema(t)
+	return name
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *Generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts := parseJSONTag(field.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fs := g.FieldSchema(field)
+		s.Properties[name] = fs
+		if hasOption(opts, "required") || hasOption(opts, "req") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// FieldSchema returns the JSON schema of the struct field,
+// with the validator expression attached and its simple constraints converted.
+func (g *Generator) FieldSchema(field reflect.StructField) *Schema {
+	s := g.Schema(field.Type)
+	if vd := field.Tag.Get(g.ValidatorTag); vd != "" {
+		s.Validator = vd
+		addConstraints(s, vd)
+	}
+	return s
+}
+
+// addConstraints converts the simple terms of the validator expression joined by '&&' to the schema constraints:
+//   $>0, $>=1, $<100, $<=99, $==1   -> minimum, maximum, exclusiveMinimum and exclusiveMaximum of the number
+//   len($)>0, mblen($)<=32          -> minLength and maxLength of the string, minItems and maxItems of the array
+//   regexp('^\w+$')                -> pattern of the string
+// NOTE:
+//  The expression with '||' or '!' is not converted, and the other terms are ignored.
+func addConstraints(s *Schema, vd string) {
+	if strings.Contains(vd, "||") || strings.Contains(strings.Replace(vd, "!=", "", -1), "!") {
+		return
+	}
+	for _, term := range strings.Split(vd, "&&") {
+		term = strings.TrimSpace(term)
+		for len(term) > 1 && term[0] == '(' && term[len(term)-1] == ')' {
+			term = strings.TrimSpace(term[1 : len(term)-1])
+		}
+		if pattern, ok := parseRegexpTerm(term); ok {
+			if s.Type == "string" {
+				s.Pattern = pattern
+			}
+			continue
+		}
+		left, op, n, ok := parseCompareTerm(term)
+		if !ok {
+			continue
+		}
+		switch {
+		case left == "$" && (s.Type == "integer" || s.Type == "number"):
+			setRange(op, n, &s.Minimum, &s.Maximum, &s.ExclusiveMinimum, &s.ExclusiveMaximum)
+		case (left == "len($)" || left == "mblen($)") && s.Type == "string":
+			setLength(op, n, &s.MinLength, &s.MaxLength)
+		case left == "len($)" && s.Type == "array":
+			setLength(op, n, &s.MinItems, &s.MaxItems)
+		}
+	}
+}
+
+// parseRegexpTerm parses the term such as regexp('^\w+$').
+func parseRegexpTerm(term string) (string, bool) {
+	const prefix = "regexp("
+	if !strings.HasPrefix(term, prefix) || !strings.HasSuffix(term, ")") {
+		return "", false
+	}
+	arg := strings.TrimSpace(term[len(prefix) : len(term)-1])
+	if len(arg) < 2 || (arg[0] != '\'' && arg[0] != '"') || arg[len(arg)-1] != arg[0] {
+		return "", false
+	}
+	return arg[1 : len(arg)-1], true
+}
+
+// parseCompareTerm parses the term comparing the left operand with a number, such as len($)<=32.
+func parseCompareTerm(term string) (left, op string, n float64, ok bool) {
+	for _, op = range []string{">=", "<=", "==", ">", "<"} {
+		if i := strings.Index(term, op); i > 0 {
+			left = strings.Replace(term[:i], " ", "", -1)
+			v, err := strconv.ParseFloat(strings.TrimSpace(term[i+len(op):]), 64)
+			return left, op, v, err == nil
+		}
+	}
+	return "", "", 0, false
+}
+
+func setRange(op string, n float64, min, max, exclusiveMin, exclusiveMax **float64) {
+	switch op {
+	case ">=":
+		*min = &n
+	case "<=":
+		*max = &n
+	case ">":
+		*exclusiveMin = &n
+	case "<":
+		*exclusiveMax = &n
+	case "==":
+		*min, *max = &n, &n
+	}
+}
+
+func setLength(op string, n float64, min, max **int) {
+	i := int(n)
+	if float64(i) != n {
+		return
+	}
+	switch op {
+	case ">=":
+		*min = &i
+	case "<=":
+		*max = &i
+	case ">":
+		i++
+		*min = &i
+	case "<":
+		i--
+		*max = &i
+	case "==":
+		*min, *max = &i, &i
+	}
+}
+
+func parseJSONTag(tag string) (name, opts string) {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return strings.TrimSpace(tag[:idx]), tag[idx+1:]
+	}
+	return strings.TrimSpace(tag), ""
+}
+
+func hasOption(opts, opt string) bool {
+	for _, s := range strings.Split(opts, ",") {
+		if strings.TrimSpace(s) == opt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package rester
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bytedance/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/henrylee2cn/rester/openapi"
+)
+
+type (
+	APIUser struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	APIUserCtl struct {
+		BaseCtl
+	}
+)
+
+func (ctl *APIUserCtl) GET(args struct {
+	ID    int64  `path:"id"`
+	Token string `header:"X-Token,required"`
+	Page  int    `query:"page" default:"1" vd:"$>0"`
+}) (*APIUser, error) {
+	return &APIUser{ID: args.ID}, nil
+}
+
+func (ctl *APIUserCtl) PUT(args struct {
+	ID   int64  `path:"id"`
+	Name string `json:"name,required"`
+	Age  int
+}) error {
+	return nil
+}
+
+func (ctl *APIUserCtl) POST() (*struct {
+	Status   int      `status:""`
+	Location string   `header:"Location"`
+	Body     *APIUser `body:""`
+}, error) {
+	return nil, nil
+}
+
+func TestOpenAPI(t *testing.T) {
+	engine := New()
+	engine.Group("/api").DefControl("/users/:id", new(APIUserCtl))
+	doc := engine.OpenAPI()
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	item := doc.Paths["/api/users/{id}"]
+	if !assert.NotNil(t, item) {
+		return
+	}
+	get := item["get"]
+	assert.Equal(t, "APIUserCtl_GET", get.OperationID)
+	assert.Len(t, get.Parameters, 3)
+	assert.Equal(t, "id", get.Parameters[0].Name)
+	assert.Equal(t, "path", get.Parameters[0].In)
+	assert.True(t, get.Parameters[0].Required)
+	assert.Equal(t, "X-Token", get.Parameters[1].Name)
+	assert.True(t, get.Parameters[1].Required)
+	assert.Equal(t, "page", get.Parameters[2].Name)
+	assert.Equal(t, float64(1), get.Parameters[2].Schema.Default)
+	assert.Equal(t, "$>0", get.Parameters[2].Schema.Validator)
+	assert.Nil(t, get.RequestBody)
+	assert.Equal(t, "#/components/schemas/APIUser", get.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.NotNil(t, doc.Components.Schemas["APIUser"])
+
+	put := item["put"]
+	assert.Len(t, put.Parameters, 1)
+	body := put.RequestBody.Content["application/json"].Schema
+	assert.True(t, put.RequestBody.Required)
+	assert.Equal(t, []string{"name"}, body.Required)
+	assert.Equal(t, "string", body.Properties["name"].Type)
+	assert.Equal(t, "integer", body.Properties["Age"].Type)
+	assert.Nil(t, put.Responses["200"].Content)
+
+	// the response struct is described by its body
+	post := item["post"]
+	assert.Equal(t, "#/components/schemas/APIUser", post.Responses["200"].Content["application/json"].Schema.Ref)
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	engine := New()
+	engine.DefControl("/users/:id", new(APIUserCtl))
+	serve := func() map[string]interface{} {
+		ctx := new(RequestCtx)
+		engine.serveOpenAPI(ctx)
+		var doc struct {
+			Paths map[string]interface{} `json:"paths"`
+		}
+		assert.NoError(t, json.Unmarshal(ctx.Response.Body(), &doc))
+		return doc.Paths
+	}
+	paths := serve()
+	assert.Contains(t, paths, "/users/{id}")
+	assert.NotContains(t, paths, "/admin/users/{id}")
+
+	// the route added after the document is served
+	engine.DefControl("/admin/users/:id", new(APIUserCtl))
+	assert.Contains(t, serve(), "/admin/users/{id}")
+
+	// the operationId is unique
+	doc := engine.OpenAPI()
+	assert.Equal(t, "APIUserCtl_GET", doc.Paths["/users/{id}"]["get"].OperationID)
+	assert.Equal(t, "APIUserCtl_GET_2", doc.Paths["/admin/users/{id}"]["get"].OperationID)
+	assert.Equal(t, "APIUserCtl_PUT_2", doc.Paths["/admin/users/{id}"]["put"].OperationID)
+}
+
+func TestServeOpenAPIConcurrently(t *testing.T) {
+	engine := New()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			engine.serveOpenAPI(new(RequestCtx))
+			engine.RoutePermissions()
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		engine.DefControl(fmt.Sprintf("/users%d/:id", i), new(APIUserCtl))
+	}
+	<-done
+	assert.Len(t, engine.OpenAPI().Paths, 10)
+}
+
+func TestOpenAPIConstraints(t *testing.T) {
+	type Args struct {
+		Page  int      `vd:"$>0 && $<=100"`
+		Score float64  `vd:"($>=0.5)&&($<1)"`
+		Name  string   `vd:"len($)>0 && mblen($)<=32 && regexp('^\\w+$')"`
+		Tags  []string `vd:"len($)<10"`
+		Kind  int      `vd:"$==1 || $==2"`
+	}
+	g := openapi.NewGenerator()
+	schema := func(name string) *openapi.Schema {
+		field, _ := reflect.TypeOf(Args{}).FieldByName(name)
+		return g.FieldSchema(field)
+	}
+	float := func(f float64) *float64 { return &f }
+	integer := func(i int) *int { return &i }
+
+	page := schema("Page")
+	assert.Equal(t, float(0), page.ExclusiveMinimum)
+	assert.Equal(t, float(100), page.Maximum)
+	assert.Equal(t, "$>0 && $<=100", page.Validator)
+
+	score := schema("Score")
+	assert.Equal(t, float(0.5), score.Minimum)
+	assert.Equal(t, float(1), score.ExclusiveMaximum)
+
+	name := schema("Name")
+	assert.Equal(t, integer(1), name.MinLength)
+	assert.Equal(t, integer(32), name.MaxLength)
+	assert.Equal(t, `^\w+$`, name.Pattern)
+
+	tags := schema("Tags")
+	assert.Equal(t, integer(9), tags.MaxItems)
+	assert.Nil(t, tags.MaxLength)
+
+	kind := schema("Kind")
+	assert.Nil(t, kind.Minimum)
+	assert.Nil(t, kind.Maximum)
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+import (
+	"errors"
+
+	"github.com/bytedance/json"
+	"github.com/henrylee2cn/goutil"
+	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester/binding"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem the RFC 7807 problem details, which is rendered as 'application/problem+json'.
+// NOTE:
+//  It can be returned by the controller methods or passed to Abort.
+type Problem struct {
+	// Type a URI reference that identifies the problem type, 'about:blank' by default
+	Type string
+	// Title a short, human-readable summary of the problem type
+	Title string
+	// Status the HTTP status code
+	Status int
+	// Detail a human-readable explanation specific to this occurrence of the problem
+	Detail string
+	// Instance a URI reference that identifies the specific occurrence of the problem
+	Instance string
+	// Extensions the extension members
+	Extensions map[string]interface{}
+}
+
+// NewProblem creates a problem with the status and detail,
+// and the title is the standard status text.
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Status: status,
+		Title:  fasthttp.StatusMessage(status),
+		Detail: detail,
+	}
+}
+
+var _ error = new(Problem)
+
+// Error implements error interface.
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+	return p.Title
+}
+
+// With sets the extension member and returns the problem itself.
+func (p *Problem) With(key string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{})
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// MarshalJSON implements json.Marshaler, the extension members are
+// encoded at the same level as the standard members.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	typ := p.Type
+	if typ == "" {
+		typ = "about:blank"
+	}
+	m["type"] = typ
+	if p.Title != "" {
+		m["title"] = p.Title
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// bindingError the error of binding or validating the request parameters
+type bindingError struct {
+	error
+}
+
+func (engine *Engine) problemJSON() bool {
+	return engine != nil && engine.ProblemJSON
+}
+
+func renderProblem(ctx *RequestCtx, p *Problem) {
+	status := p.Status
+	if status == 0 {
+		status = fasthttp.StatusInternalServerError
+	}
+	if p.Instance == "" {
+		p.Instance = string(ctx.Path())
+	}
+	renderJSONAs(ctx, status, problemContentType, p)
+}
+
+func codeMsgProblem(status int, c *CodeMsg) *Problem {
+	return NewProblem(status, c.Msg).With("code", c.Code)
+}
+
+// errorProblem converts the error and the body that would be rendered to the problem,
+// mapped is true if the error is mapped by Engine.ErrorMapper.
+func errorProblem(ctx *RequestCtx, status int, err error, body interface{}, mapped bool) *Problem {
+	if be, ok := err.(*bindingError); ok {
+		p := NewProblem(status, be.Error())
+		var errs binding.Errors
+		if errors.As(be.error, &errs) {
+			p.With("errors", errs)
+		}
+		return p
+	}
+	if c, ok := body.(*CodeMsg); ok {
+		return codeMsgProblem(status, c)
+	}
+	if status >= fasthttp.StatusInternalServerError && !mapped {
+		// NOTE:
+		//  Do not leak the unmapped internal error.
+		ctx.Logger().Printf("error=%s", err.Error())
+		return NewProblem(status, "")
+	}
+	return NewProblem(status, err.Error()).With("body", body)
+}
+
+func problemNotFound(ctx *RequestCtx) {
+	renderProblem(ctx, NewProblem(fasthttp.StatusNotFound, ""))
+}
+
+func problemMethodNotAllowed(ctx *RequestCtx) {
+	renderProblem(ctx, NewProblem(fasthttp.StatusMethodNotAllowed, "").
+		With("allow", string(ctx.Response.Header.Peek("Allow"))))
+}
+
+func problemPanic(ctx *RequestCtx, rcv interface{}) {
+	ctx.Logger().Printf("panic=%v\n%s", rcv, goutil.PanicTrace(2))
+	renderProblem(ctx, NewProblem(fasthttp.StatusInternalServerError, ""))
+}
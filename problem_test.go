@@ -0,0 +1,97 @@
+package rester
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type ProblemCtl struct {
+	BaseCtl
+}
+
+func (ctl *ProblemCtl) GET(args *struct {
+	Kind int `query:"kind,required" vd:"$<9"`
+}) error {
+	switch args.Kind {
+	case 0:
+		return &Problem{Type: "https://example.com/out-of-credit", Title: "You do not have enough credit.", Status: 403,
+			Extensions: map[string]interface{}{"balance": 30}}
+	case 1:
+		ctl.Forbidden(1001, "forbidden")
+	case 2:
+		return errors.New("secret")
+	case 3:
+		panic("boom")
+	case 4:
+		return sliceError{errors.New("a"), errors.New("b")}
+	}
+	return nil
+}
+
+// sliceError the error type that cannot be compared
+type sliceError []error
+
+func (e sliceError) Error() string {
+	return fmt.Sprint([]error(e))
+}
+
+func serveProblem(engine *Engine, method, uri string) *fasthttp.RequestCtx {
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Init(new(fasthttp.Request), nil, nil)
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(uri)
+	engine.Router.router.Handler(ctx)
+	return ctx
+}
+
+func TestProblemJSON(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	engine := New()
+	engine.ProblemJSON = true
+	engine.HandleMethodNotAllowed = true
+	engine.DefControl("/p", new(ProblemCtl))
+	engine.initOnce()
+
+	var cases = []struct {
+		method string
+		uri    string
+		status int
+		body   string
+	}{
+		{"GET", "/p?kind=0", 403, `{"balance":30,"instance":"/p","status":403,"title":"You do not have enough credit.","type":"https://example.com/out-of-credit"}`},
+		{"GET", "/p?kind=1", 403, `{"code":1001,"detail":"forbidden","instance":"/p","status":403,"title":"Forbidden","type":"about:blank"}`},
+		{"GET", "/p?kind=2", 500, `{"instance":"/p","status":500,"title":"Internal Server Error","type":"about:blank"}`},
+		{"GET", "/p?kind=3", 500, `{"instance":"/p","status":500,"title":"Internal Server Error","type":"about:blank"}`},
+		{"GET", "/p?kind=4", 500, `{"instance":"/p","status":500,"title":"Internal Server Error","type":"about:blank"}`},
+		{"GET", "/p?kind=x", 400, ""},
+		{"GET", "/none", 404, `{"instance":"/none","status":404,"title":"Not Found","type":"about:blank"}`},
+		{"POST", "/p", 405, `{"allow":"GET, OPTIONS","instance":"/p","status":405,"title":"Method Not Allowed","type":"about:blank"}`},
+	}
+	for _, c := range cases {
+		ctx := serveProblem(engine, c.method, c.uri)
+		assert.Equal(t, c.status, ctx.Response.StatusCode(), c.uri)
+		assert.Equal(t, problemContentType, string(ctx.Response.Header.ContentType()), c.uri)
+		if c.body != "" {
+			assert.Equal(t, c.body, string(ctx.Response.Body()), c.uri)
+		}
+	}
+}
+
+func TestProblemBindingErrors(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	engine := New()
+	engine.ProblemJSON = true
+	b := NewBinding().SetCollectAllErrors(true)
+	handlers, _, err := newHandlers(new(ProblemCtl), nil, handlerOptions{engine: engine, binding: b})
+	assert.NoError(t, err)
+
+	ctx := serveTest(handlers, "GET", "/?kind=9")
+	assert.Equal(t, 400, ctx.Response.StatusCode())
+	assert.Contains(t, string(ctx.Response.Body()), `"errors":[{"field":"Kind","in":"query","value":"9","reason":"invalid","message":`)
+}
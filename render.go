@@ -0,0 +1,265 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/json"
+	"github.com/gogo/protobuf/proto"
+	"github.com/henrylee2cn/ameda"
+	"github.com/valyala/fasthttp"
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/henrylee2cn/rester/binding"
+)
+
+// Renderer encodes the response value to the body
+type Renderer func(value interface{}) ([]byte, error)
+
+type rendererEntry struct {
+	mediaType   string
+	contentType string
+	render      Renderer
+}
+
+var renderers struct {
+	sync.RWMutex
+	list []rendererEntry
+}
+
+func init() {
+	RegisterRenderer(jsonContentType, json.Marshal)
+	RegisterRenderer("application/xml; charset=utf-8", xml.Marshal)
+	RegisterRenderer("application/x-protobuf", renderProtobuf)
+	RegisterRenderer("application/msgpack", renderMsgpack)
+	RegisterRenderer("text/plain; charset=utf-8", renderText)
+}
+
+// RegisterRenderer registers the renderer of the content type, such as 'application/json; charset=utf-8'.
+// NOTE:
+//  The renderer replaces the registered one with the same media type;
+//  When the request accepts any media type, the earliest registered renderer is preferred,
+//  the built-in order is JSON, XML, protobuf, MessagePack and plain text.
+func RegisterRenderer(contentType string, renderer Renderer) {
+	mediaType := parseMediaType(contentType)
+	renderers.Lock()
+	defer renderers.Unlock()
+	for i, e := range renderers.list {
+		if e.mediaType == mediaType {
+			renderers.list[i] = rendererEntry{mediaType, contentType, renderer}
+			return
+		}
+	}
+	renderers.list = append(renderers.list, rendererEntry{mediaType, contentType, renderer})
+}
+
+func renderProtobuf(value interface{}) ([]byte, error) {
+	msg, ok := value.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a proto.Message", value)
+	}
+	return proto.Marshal(msg)
+}
+
+func renderMsgpack(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := msgpack.NewEncoder(&buf).UseJSONTag(true).Encode(value)
+	return buf.Bytes(), err
+}
+
+// renderText renders string, []byte, fmt.Stringer and encoding.TextMarshaler,
+// other values are not rendered lest the fields hidden from JSON leak.
+func renderText(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return ameda.UnsafeStringToBytes(v), nil
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	case fmt.Stringer:
+		return ameda.UnsafeStringToBytes(v.String()), nil
+	}
+	return nil, fmt.Errorf("%T cannot be rendered as text", value)
+}
+
+// respond renders the value, or the status code, header, cookie and body of the response struct,
+// the header and cookie are written only if the body is rendered.
+func respond(engine *Engine, ctx *RequestCtx, code int, value interface{}) {
+	var resp binding.Response
+	var ok bool
+	var err error
+	if engine != nil && engine.headerWriter != nil {
+		resp, ok, err = engine.headerWriter.ReadResponse(value)
+	} else {
+		resp, ok, err = binding.ReadResponse(value)
+	}
+	if err != nil {
+		renderError(engine, ctx, err)
+		return
+	}
+	if ok {
+		if resp.StatusCode != 0 {
+			code = resp.StatusCode
+		}
+		if resp.Body == nil {
+			resp.WriteHeader(ctx)
+			ctx.SetStatusCode(code)
+			ctx.ResetBody()
+			return
+		}
+		value = resp.Body
+	}
+	render(engine, ctx, code, value, resp.WriteHeader)
+}
+
+var errNotAcceptable = errors.New("none of the accepted media types can be rendered")
+
+// render encodes the value with the renderers negotiated by the 'Accept' header,
+// it tries the next acceptable renderer if one fails, and responds 406 Not Acceptable if all fail.
+// writeHeader is called only after the value is rendered, so that no header of the response struct is written on failure.
+func render(engine *Engine, ctx *RequestCtx, code int, value interface{}, writeHeader func(*RequestCtx)) {
+	ctx.Response.Header.Add(fasthttp.HeaderVary, "Accept")
+	for _, e := range negotiateRenderers(ameda.UnsafeBytesToString(ctx.Request.Header.Peek(fasthttp.HeaderAccept))) {
+		bodyBytes, err := e.render(value)
+		if err != nil {
+			ctx.Logger().Printf("render %s error=%s", e.mediaType, err.Error())
+			continue
+		}
+		writeHeader(ctx)
+		renderBytes(ctx, code, e.contentType, bodyBytes)
+		return
+	}
+	status := fasthttp.StatusNotAcceptable
+	if engine.problemJSON() {
+		renderProblem(ctx, NewProblem(status, errNotAcceptable.Error()))
+	} else {
+		renderJSON(ctx, status, &CodeMsg{Code: status, Msg: errNotAcceptable.Error()})
+	}
+}
+
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// specificity returns 2 for 'type/subtype', 1 for 'type/*' and 0 for '*/*'.
+func (a acceptRange) specificity() int {
+	if a.typ == "*" {
+		return 0
+	}
+	if a.subtype == "*" {
+		return 1
+	}
+	return 2
+}
+
+func (a acceptRange) match(mediaType string) bool {
+	if a.typ == "*" {
+		return true
+	}
+	i := strings.IndexByte(mediaType, '/')
+	if i < 0 || mediaType[:i] != a.typ {
+		return false
+	}
+	return a.subtype == "*" || mediaType[i+1:] == a.subtype
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, s := range strings.Split(accept, ",") {
+		params := strings.Split(s, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		r := acceptRange{q: 1}
+		if i := strings.IndexByte(mediaType, '/'); i > 0 {
+			r.typ, r.subtype = mediaType[:i], mediaType[i+1:]
+		} else if mediaType == "*" {
+			r.typ, r.subtype = "*", "*"
+		} else {
+			continue
+		}
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if len(p) > 2 && (p[0] == 'q' || p[0] == 'Q') && p[1] == '=' {
+				if q, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// negotiateRenderers returns the acceptable renderers from the highest quality to the lowest,
+// the quality of a renderer comes from the most specific matching range,
+// and the more specific range or the earlier registered renderer is preferred for the same quality.
+func negotiateRenderers(accept string) []rendererEntry {
+	renderers.RLock()
+	defer renderers.RUnlock()
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return append([]rendererEntry(nil), renderers.list...)
+	}
+	type candidate struct {
+		rendererEntry
+		q    float64
+		spec int
+	}
+	var candidates []candidate
+	for _, e := range renderers.list {
+		spec := -1
+		var q float64
+		for _, r := range ranges {
+			if r.match(e.mediaType) && r.specificity() > spec {
+				spec, q = r.specificity(), r.q
+			}
+		}
+		if spec < 0 || q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{e, q, spec})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return candidates[i].spec > candidates[j].spec
+	})
+	list := make([]rendererEntry, len(candidates))
+	for i, c := range candidates {
+		list[i] = c.rendererEntry
+	}
+	return list
+}
+
+func parseMediaType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
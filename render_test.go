@@ -0,0 +1,203 @@
+package rester
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/vmihailenco/msgpack/v4"
+)
+
+func TestNegotiateRenderers(t *testing.T) {
+	const (
+		j = "application/json"
+		x = "application/xml"
+		p = "application/x-protobuf"
+		m = "application/msgpack"
+		s = "text/plain"
+	)
+	var cases = []struct {
+		accept     string
+		mediaTypes []string
+	}{
+		{"", []string{j, x, p, m, s}},
+		{"*/*", []string{j, x, p, m, s}},
+		{"application/xml", []string{x}},
+		{"text/*;q=0.5, application/x-protobuf;q=0.9", []string{p, s}},
+		{"application/json;q=0, */*;q=0.1", []string{x, p, m, s}},
+		{"text/html, application/*;q=0.2, text/plain;q=0.3", []string{s, j, x, p, m}},
+		{"text/*;q=0.5, application/json;q=0.5, application/*;q=0.8", []string{x, p, m, j, s}},
+		{"application/msgpack", []string{m}},
+		{"image/png", nil},
+	}
+	for _, c := range cases {
+		var mediaTypes []string
+		for _, e := range negotiateRenderers(c.accept) {
+			mediaTypes = append(mediaTypes, e.mediaType)
+		}
+		assert.Equal(t, c.mediaTypes, mediaTypes, c.accept)
+	}
+}
+
+type textStringer struct {
+	Name   string
+	Secret string `json:"-"`
+}
+
+func (s textStringer) String() string {
+	return s.Name
+}
+
+func TestRenderText(t *testing.T) {
+	var cases = []struct {
+		value interface{}
+		text  string
+	}{
+		{"abc", "abc"},
+		{[]byte("abc"), "abc"},
+		{textStringer{Name: "abc", Secret: "x"}, "abc"},
+		{net.IPv4(127, 0, 0, 1), "127.0.0.1"},
+		{struct{ Secret string }{"x"}, ""},
+		{H{"id": 1}, ""},
+	}
+	for _, c := range cases {
+		b, err := renderText(c.value)
+		assert.Equal(t, c.text == "", err != nil, c.value)
+		assert.Equal(t, c.text, string(b), c.value)
+	}
+}
+
+type RenderCtl struct {
+	BaseCtl
+}
+
+func (ctl *RenderCtl) GET() {
+	ctl.OK(H{"id": 1})
+}
+
+func TestRender(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	handlers, _, err := newHandlers(new(RenderCtl), nil, handlerOptions{engine: New()})
+	assert.NoError(t, err)
+
+	serve := func(accept string) *RequestCtx {
+		ctx := new(RequestCtx)
+		ctx.Init(new(fasthttp.Request), nil, nil)
+		ctx.Request.Header.Set("Accept", accept)
+		handlers["GET"](ctx)
+		return ctx
+	}
+	// the map is not rendered as text
+	ctx := serve("text/plain")
+	assert.Equal(t, 406, ctx.Response.StatusCode())
+	assert.Equal(t, "Accept", string(ctx.Response.Header.Peek("Vary")))
+
+	ctx = serve("application/msgpack")
+	assert.Equal(t, "application/msgpack", string(ctx.Response.Header.ContentType()))
+	var m map[string]int
+	assert.NoError(t, msgpack.Unmarshal(ctx.Response.Body(), &m))
+	assert.Equal(t, 1, m["id"])
+
+	// the failed renderer falls back to the next acceptable one
+	ctx = serve("application/xml, application/json;q=0.5")
+	assert.Equal(t, 200, ctx.Response.StatusCode())
+	assert.Equal(t, "application/json; charset=utf-8", string(ctx.Response.Header.ContentType()))
+	assert.Equal(t, `{"id":1}`, string(ctx.Response.Body()))
+
+	ctx = serve("application/x-protobuf")
+	assert.Equal(t, 406, ctx.Response.StatusCode())
+	assert.Equal(t, `{"code":406,"msg":"none of the accepted media types can be rendered"}`, string(ctx.Response.Body()))
+
+	ctx = serve("image/png")
+	assert.Equal(t, 406, ctx.Response.StatusCode())
+	assert.Equal(t, `{"code":406,"msg":"none of the accepted media types can be rendered"}`, string(ctx.Response.Body()))
+
+	engine := New()
+	engine.ProblemJSON = true
+	handlers, _, err = newHandlers(new(RenderCtl), nil, handlerOptions{engine: engine})
+	assert.NoError(t, err)
+	ctx = serve("application/x-protobuf")
+	assert.Equal(t, 406, ctx.Response.StatusCode())
+	assert.Equal(t, problemContentType, string(ctx.Response.Header.ContentType()))
+}
+
+type createdUser struct {
+	Status   int      `status:""`
+	Location string   `header:"Location,required"`
+	Links    []string `header:"Link"`
+	Session  string   `cookie:"session"`
+	Body     H        `body:""`
+}
+
+type ResponseCtl struct {
+	BaseCtl
+}
+
+func (ctl *ResponseCtl) GET(args struct {
+	Location string `query:"location"`
+}) {
+	ctl.OK(&createdUser{
+		Status:   201,
+		Location: args.Location,
+		Links:    []string{"</users>; rel=collection", "</users/1/posts>; rel=posts"},
+		Session:  "abc",
+		Body:     H{"id": 1},
+	})
+}
+
+func (ctl *ResponseCtl) POST() (*createdUser, error) {
+	return &createdUser{Location: "/users/2"}, nil
+}
+
+func TestRespondStruct(t *testing.T) {
+	useTestMode = false
+	defer func() { useTestMode = true }()
+	handlers, _, err := newHandlers(new(ResponseCtl), nil, handlerOptions{engine: New()})
+	assert.NoError(t, err)
+
+	serve := func(method, uri string, accept ...string) *RequestCtx {
+		ctx := new(RequestCtx)
+		ctx.Init(new(fasthttp.Request), nil, nil)
+		ctx.Request.SetRequestURI(uri)
+		if len(accept) > 0 {
+			ctx.Request.Header.Set("Accept", accept[0])
+		}
+		handlers[method](ctx)
+		return ctx
+	}
+	ctx := serve("GET", "/?location=/users/1")
+	assert.Equal(t, 201, ctx.Response.StatusCode())
+	assert.Equal(t, "/users/1", string(ctx.Response.Header.Peek("Location")))
+	var links []string
+	ctx.Response.Header.VisitAll(func(k, v []byte) {
+		if string(k) == "Link" {
+			links = append(links, string(v))
+		}
+	})
+	assert.Equal(t, []string{"</users>; rel=collection", "</users/1/posts>; rel=posts"}, links)
+	var ck fasthttp.Cookie
+	assert.NoError(t, ck.ParseBytes(ctx.Response.Header.PeekCookie("session")))
+	assert.Equal(t, "abc", string(ck.Value()))
+	assert.Equal(t, `{"id":1}`, string(ctx.Response.Body()))
+
+	// the returned response without status and body
+	ctx = serve("POST", "/")
+	assert.Equal(t, 200, ctx.Response.StatusCode())
+	assert.Equal(t, "/users/2", string(ctx.Response.Header.Peek("Location")))
+	assert.Empty(t, ctx.Response.Body())
+
+	// the missing required header
+	ctx = serve("GET", "/")
+	assert.Equal(t, 500, ctx.Response.StatusCode())
+	assert.Empty(t, ctx.Response.Header.Peek("Link"))
+	assert.Empty(t, ctx.Response.Header.PeekCookie("session"))
+
+	// the body that cannot be rendered
+	ctx = serve("GET", "/?location=/users/1", "image/png")
+	assert.Equal(t, 406, ctx.Response.StatusCode())
+	assert.Empty(t, ctx.Response.Header.Peek("Location"))
+	assert.Empty(t, ctx.Response.Header.Peek("Link"))
+	assert.Empty(t, ctx.Response.Header.PeekCookie("session"))
+}
@@ -17,10 +17,14 @@ package rester
 import (
 	"net"
 	"os"
+	"reflect"
 	"sync"
 	"time"
 
 	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester/binding"
+	"github.com/henrylee2cn/rester/openapi"
 )
 
 // alias
@@ -122,6 +126,43 @@ type Engine struct {
 	// unrecovered panics.
 	PanicHandler func(*fasthttp.RequestCtx, interface{})
 
+	// Function to map the error returned or aborted by controllers to the response.
+	// It is consulted for errors other than *CodeMsg, and the error is not mapped
+	// if the returned status is 0.
+	// If the returned body is nil, *CodeMsg{Code:status, Msg:err.Error()} is used.
+	// By default unmapped errors respond 500 (Internal Server Error).
+	ErrorMapper func(err error) (status int, body interface{})
+
+	// If enabled, the framework errors are rendered as RFC 7807 'application/problem+json',
+	// including the binding failures, the aborted errors, the panics, 404 and 405.
+	// NOTE:
+	//  The NotFound, MethodNotAllowed and PanicHandler are used if they are set.
+	ProblemJSON bool
+
+	// -------------- openapi ----------------
+
+	// The route path to serve the OpenAPI document of the registered controllers in JSON,
+	// such as '/openapi.json'.
+	// The document is not served if it is empty.
+	OpenAPIPath string
+
+	// The metadata of the OpenAPI document.
+	// By default the title is Name and the version is '1.0.0'.
+	OpenAPIInfo openapi.Info
+
+	// openAPIBody the cached document, which is reset when a route is added
+	openAPIBody []byte
+	openAPILock sync.Mutex
+
+	// -------------- injection ----------------
+
+	providers map[reflect.Type]*provider
+
+	// -------------- cookie ----------------
+
+	keyring      *binding.Keyring
+	headerWriter *binding.HeaderWriter
+
 	// -------------- server ----------------
 
 	server fasthttp.Server
@@ -358,6 +399,10 @@ func New() *Engine {
 		HandleMethodNotAllowed: false,
 		HandleOPTIONS:          true,
 	}
+	engine.Router.engine = engine
+	engine.keyring = binding.NewKeyring()
+	engine.Router.binding = NewBinding().SetKeyring(engine.keyring)
+	engine.headerWriter = binding.NewHeaderWriter(nil).SetKeyring(engine.keyring)
 	return engine
 }
 
@@ -368,6 +413,18 @@ func (engine *Engine) initOnce() {
 			engine.Name = "rester"
 		}
 
+		if engine.ProblemJSON {
+			if engine.NotFound == nil {
+				engine.NotFound = problemNotFound
+			}
+			if engine.MethodNotAllowed == nil {
+				engine.MethodNotAllowed = problemMethodNotAllowed
+			}
+			if engine.PanicHandler == nil {
+				engine.PanicHandler = problemPanic
+			}
+		}
+
 		// router
 		engine.Router.router.RedirectTrailingSlash = engine.RedirectTrailingSlash
 		engine.Router.router.RedirectFixedPath = engine.RedirectFixedPath
@@ -376,6 +433,9 @@ func (engine *Engine) initOnce() {
 		engine.Router.router.NotFound = engine.NotFound
 		engine.Router.router.MethodNotAllowed = engine.MethodNotAllowed
 		engine.Router.router.PanicHandler = engine.PanicHandler
+		if engine.OpenAPIPath != "" {
+			engine.Router.router.GET(engine.OpenAPIPath, engine.serveOpenAPI)
+		}
 		// server
 		engine.server.Handler = engine.Router.router.Handler
 		engine.server.ErrorHandler = engine.ErrorHandler
@@ -515,7 +575,9 @@ func (engine *Engine) ServeConn(c net.Conn) error {
 // Shutdown does not close keepalive connections so its recommended to set ReadTimeout to something else than 0.
 func (engine *Engine) Shutdown() error {
 	engine.initOnce()
-	return engine.server.Shutdown()
+	err := engine.server.Shutdown()
+	engine.cleanupSingletons()
+	return err
 }
 
 // GetCurrentConcurrency returns a number of currently served
@@ -17,15 +17,115 @@ package rester
 import (
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/buaazp/fasthttprouter"
 	"github.com/henrylee2cn/ameda"
+
+	"github.com/henrylee2cn/rester/binding"
 )
 
 // Router HTTP router
 type Router struct {
 	router          fasthttprouter.Router
 	controllerNames map[string]string // {controllerName:relativePath}
+	engine          *Engine
+	parent          *Router
+	prefix          string
+	middlewares     []Controller
+	cors            *CORSConfig
+	binding         *binding.Binding
+	policy          Policy
+	// routes the registered routes of the root router, which are read while routes are added
+	routes     []*route
+	routesLock sync.RWMutex
+}
+
+// route the registered controller route
+type route struct {
+	httpMethod string
+	path       string
+	controller reflect.Type
+	// methods the chain methods in execution order, the last one is the controller's own method
+	methods     []reflect.Method
+	binding     *binding.Binding
+	permissions []string
+}
+
+// Group creates a sub-router whose routes share the path prefix,
+// and the middleware controllers are executed in order before every controller registered in it.
+// NOTE:
+//  The middleware controller is found by its HTTP method name or 'Any' method, for example:
+//    func (mw *AuthCtl) Any() {...}
+//  Calling Next() in the middleware continues into the following middleware and controller;
+//  Each request uses a shallow copy of the middleware controller;
+//  Groups can be nested, the middlewares of the parent group are executed first.
+func (r *Router) Group(prefix string, mw ...Controller) *Router {
+	if prefix == "" || prefix[0] != '/' {
+		panic("group prefix must begin with '/' in prefix '" + prefix + "'")
+	}
+	middlewares := make([]Controller, 0, len(r.middlewares)+len(mw))
+	middlewares = append(middlewares, r.middlewares...)
+	middlewares = append(middlewares, mw...)
+	return &Router{
+		parent:      r,
+		prefix:      r.prefix + strings.TrimSuffix(prefix, "/"),
+		middlewares: middlewares,
+	}
+}
+
+// SetCORS sets the CORS policy of the CORS_* methods of the controllers registered later,
+// including the controllers in the sub-groups.
+// NOTE:
+//  The policy provided by the controller itself through CORSProvider takes precedence.
+func (r *Router) SetCORS(config *CORSConfig) {
+	r.cors = config
+}
+
+// corsConfig returns the CORS policy of the nearest router.
+func (r *Router) corsConfig() *CORSConfig {
+	for ; r != nil; r = r.parent {
+		if r.cors != nil {
+			return r.cors
+		}
+	}
+	return nil
+}
+
+// SetBinding sets the binding of the request arguments of the controllers registered later,
+// including the controllers in the sub-groups.
+// NOTE:
+//  The engine created by New uses its own binding created by NewBinding, with the keyring of the engine;
+//  The binding is not changed by the engine, so it needs the keyring set by itself to read the signed or encrypted cookies,
+//  e.g. b.SetKeyring(engine.Keyring()).
+func (r *Router) SetBinding(b *binding.Binding) {
+	r.binding = b
+}
+
+// bindingOf returns the binding of the nearest router.
+func (r *Router) bindingOf() *binding.Binding {
+	for ; r != nil; r = r.parent {
+		if r.binding != nil {
+			return r.binding
+		}
+	}
+	return nil
+}
+
+// routeList returns the snapshot of the registered routes.
+func (r *Router) routeList() []*route {
+	root := r.root()
+	root.routesLock.RLock()
+	defer root.routesLock.RUnlock()
+	return append([]*route(nil), root.routes...)
+}
+
+func (r *Router) root() *Router {
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
 }
 
 // Control registers route with controller factory.
@@ -48,21 +148,44 @@ func (r *Router) control(path string, controller Controller, factory func() Cont
 	if factory != nil {
 		controller = factory()
 	}
-	if r.controllerNames == nil {
-		r.controllerNames = make(map[string]string)
+	root := r.root()
+	if root.controllerNames == nil {
+		root.controllerNames = make(map[string]string)
 	}
-	var handlerMap map[string]RequestHandler
-	if factory != nil {
-		handlerMap = MustMakeHandlers(factory)
-	} else {
-		handlerMap = MustNewHandlers(controller)
+	bind := r.bindingOf()
+	if bind == nil {
+		bind = NewBinding()
 	}
+	handlerMap, chains, err := newHandlers(controller, factory, handlerOptions{
+		engine:      root.engine,
+		cors:        r.corsConfig(),
+		binding:     bind,
+		policy:      r.policyOf(),
+		middlewares: r.middlewares,
+	})
+	checkNewChainErr(err)
+	path = r.prefix + path
 	controllerName := getControllerName(controller)
 	for _, httpMethod := range httpMethodList {
 		handler := handlerMap[httpMethod]
 		if handler != nil {
-			r.router.Handle(httpMethod, path, handler)
-			r.controllerNames[controllerName] = path
+			root.router.Handle(httpMethod, path, handler)
+			root.controllerNames[controllerName] = path
+			if ch := chains[httpMethod]; len(ch.methods) > 0 {
+				root.routesLock.Lock()
+				root.routes = append(root.routes, &route{
+					httpMethod:  httpMethod,
+					path:        path,
+					controller:  ameda.DereferenceType(reflect.TypeOf(controller)),
+					methods:     ch.methods,
+					binding:     bind,
+					permissions: ch.permissions,
+				})
+				root.routesLock.Unlock()
+				if root.engine != nil {
+					root.engine.resetOpenAPI()
+				}
+			}
 			r.println(httpMethod, path, controllerName)
 		}
 	}
@@ -77,7 +200,8 @@ func (r *Router) control(path string, controller Controller, factory func() Cont
 // of the Router's NotFound handler.
 //     router.ServeFiles("/src/*filepath", "/var/www")
 func (r *Router) ServeFiles(path string, rootPath string) {
-	r.router.ServeFiles(path, rootPath)
+	path = r.prefix + path
+	r.root().router.ServeFiles(path, rootPath)
 	r.println("GET", path, "fasthttp.FSHandler")
 }
 
@@ -85,7 +209,7 @@ func (r *Router) ServeFiles(path string, rootPath string) {
 // NOTE:
 //  Must be called after routing
 func (r *Router) Path(controller Controller) string {
-	return r.controllerNames[getControllerName(controller)]
+	return r.root().controllerNames[getControllerName(controller)]
 }
 
 func (r *Router) println(httpMethod, path, controllerName string) {
@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester/binding"
 )
 
 type Ctl1 struct {
@@ -52,3 +55,77 @@ func TestRouter_DefControl2(t *testing.T) {
 	}()
 	r.DefControl("/", &Ctl3{})
 }
+
+type GroupMwCtl struct {
+	BaseCtl
+	name string
+}
+
+func (ctl *GroupMwCtl) Any() {
+	trace, _ := ctl.UserValue("trace").(string)
+	ctl.SetUserValue("trace", trace+ctl.name+">")
+	ctl.Next()
+	trace, _ = ctl.UserValue("trace").(string)
+	ctl.SetUserValue("trace", trace+"<"+ctl.name)
+}
+
+type GroupCtl struct {
+	BaseCtl
+}
+
+func (ctl *GroupCtl) GET() {
+	trace, _ := ctl.UserValue("trace").(string)
+	ctl.SetUserValue("trace", trace+"ctl")
+}
+
+func TestRouter_Group(t *testing.T) {
+	var r Router
+	api := r.Group("/api/", &GroupMwCtl{name: "api"})
+	v1 := api.Group("/v1", &GroupMwCtl{name: "v1"})
+	v1.DefControl("/users", new(GroupCtl))
+	assert.Equal(t, "/api/v1/users", r.Path(new(GroupCtl)))
+
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod("GET")
+	ctx.Request.SetRequestURI("/api/v1/users")
+	r.router.Handler(ctx)
+	assert.Equal(t, "api>v1>ctl<v1<api", ctx.UserValue("trace"))
+}
+
+type BindingCtl struct {
+	BaseCtl
+}
+
+func (ctl *BindingCtl) GET(args struct {
+	Q string `q:"qq"`
+}) {
+	ctl.SetUserValue("q", args.Q)
+}
+
+func TestRouter_SetBinding(t *testing.T) {
+	engine := New()
+	engine.DefControl("/a", new(BindingCtl))
+	g := engine.Group("/g")
+	g.SetBinding(binding.New(&binding.Config{Query: "q"}))
+	g.Group("/sub").DefControl("/b", new(BindingCtl))
+	other := New()
+	other.SetBinding(binding.New(&binding.Config{Query: "q"}))
+	other.DefControl("/c", new(BindingCtl))
+
+	for _, c := range []struct {
+		router *Router
+		uri    string
+		q      string
+	}{
+		{&engine.Router, "/a?qq=1", ""},
+		{&engine.Router, "/g/sub/b?qq=2", "2"},
+		{&other.Router, "/c?qq=3", "3"},
+	} {
+		ctx := new(fasthttp.RequestCtx)
+		ctx.Request.Header.SetMethod("GET")
+		ctx.Request.SetRequestURI(c.uri)
+		c.router.router.Handler(ctx)
+		assert.Equal(t, c.q, ctx.UserValue("q"))
+	}
+	assert.True(t, New().bindingOf() != engine.bindingOf())
+}
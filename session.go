@@ -0,0 +1,60 @@
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rester
+
+// Session the server-side session of the request,
+// which is loaded by the middleware of the package 'github.com/henrylee2cn/rester/session'.
+type Session interface {
+	// ID returns the session ID.
+	ID() string
+	// Get returns the value of the key, or nil if it is not found.
+	Get(key string) interface{}
+	// Set sets the value of the key.
+	Set(key string, value interface{})
+	// Delete deletes the value of the key.
+	Delete(key string)
+	// Clear deletes all the values.
+	Clear()
+	// Regenerate changes the session ID and keeps the values,
+	// it should be called when the privilege changes, such as login, to prevent session fixation.
+	Regenerate()
+	// Destroy deletes the session from the store and expires its cookie.
+	Destroy()
+	// AddFlash adds the flash message, which is kept until it is read by Flashes.
+	AddFlash(value interface{})
+	// Flashes returns and deletes the flash messages.
+	Flashes() []interface{}
+}
+
+const sessionUserValueKey = "__rester_session__"
+
+// SetSession sets the session of the request, it is called by the session middleware.
+func SetSession(ctx *RequestCtx, s Session) {
+	ctx.SetUserValue(sessionUserValueKey, s)
+}
+
+// GetSession returns the session of the request, or nil if no session middleware is executed.
+func GetSession(ctx *RequestCtx) Session {
+	s, _ := ctx.UserValue(sessionUserValueKey).(Session)
+	return s
+}
+
+// Session returns the session of the request, or nil if no session middleware is executed.
+func (b BaseCtl) Session() Session {
+	if b.RequestCtx == nil {
+		return nil
+	}
+	return GetSession(b.RequestCtx)
+}
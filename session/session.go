@@ -0,0 +1,322 @@
+// Package session the server-side sessions with the pluggable stores,
+// which are loaded by the middleware controller before the chain runs, and saved after it.
+//
+// Copyright 2020 HenryLee. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package session
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"io"
+	"time"
+
+	"github.com/henrylee2cn/goutil"
+	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester"
+)
+
+// Config the session cookie and timeouts
+type Config struct {
+	// CookieName use 'session_id' by default when empty
+	CookieName string
+	// CookiePath use '/' by default when empty
+	CookiePath string
+	// CookieDomain the domain of the cookie, the host of the request when empty
+	CookieDomain string
+	// CookieSecure if set to true, the cookie is only sent over HTTPS
+	CookieSecure bool
+	// CookieSameSite use fasthttp.CookieSameSiteLaxMode by default when zero
+	CookieSameSite fasthttp.CookieSameSite
+	// IdleTimeout the session expires if it is not accessed within the duration,
+	// use 30 minutes by default when zero, and it is disabled when negative
+	IdleTimeout time.Duration
+	// AbsoluteTimeout the session expires after the duration since it is created, whether it is accessed or not,
+	// use 24 hours by default when zero, and it is disabled when negative
+	AbsoluteTimeout time.Duration
+}
+
+const (
+	defaultIdleTimeout     = 30 * time.Minute
+	defaultAbsoluteTimeout = 24 * time.Hour
+)
+
+func (c *Config) init() {
+	goutil.InitAndGetString(&c.CookieName, "session_id")
+	goutil.InitAndGetString(&c.CookiePath, "/")
+	if c.CookieSameSite == fasthttp.CookieSameSiteDisabled {
+		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = defaultIdleTimeout
+	}
+	if c.AbsoluteTimeout == 0 {
+		c.AbsoluteTimeout = defaultAbsoluteTimeout
+	}
+}
+
+// Middleware the middleware controller which loads the session before the chain runs,
+// and saves it after Next() returns, e.g.
+//   api := engine.Group("/api", session.NewMiddleware(session.NewMemoryStore(), nil))
+// NOTE:
+//  The controllers get the session by BaseCtl.Session().
+type Middleware struct {
+	rester.BaseCtl
+	manager *manager
+}
+
+// NewMiddleware creates the session middleware controller,
+// the default config is used if config is nil.
+func NewMiddleware(store Store, config *Config) *Middleware {
+	if config == nil {
+		config = new(Config)
+	}
+	m := &manager{store: store, config: *config}
+	m.config.init()
+	return &Middleware{manager: m}
+}
+
+// Any loads the session, executes the chain and saves the session.
+func (m *Middleware) Any() error {
+	s, err := m.manager.load(m.RequestCtx)
+	if err != nil {
+		return err
+	}
+	rester.SetSession(m.RequestCtx, s)
+	m.Next()
+	return m.manager.save(m.RequestCtx, s)
+}
+
+// now returns the current time, it is replaced in tests.
+var now = time.Now
+
+type manager struct {
+	store  Store
+	config Config
+}
+
+// record the stored data of the session
+type record struct {
+	Values   map[string]interface{}
+	Flashes  []interface{}
+	Created  time.Time
+	Accessed time.Time
+}
+
+// load loads the session of the cookie, or creates a new one if it is not found or expired.
+func (m *manager) load(ctx *rester.RequestCtx) (*Session, error) {
+	t := now()
+	if id := string(ctx.Request.Header.Cookie(m.config.CookieName)); id != "" {
+		data, err := m.store.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			var rec record
+			if gob.NewDecoder(bytes.NewReader(data)).Decode(&rec) == nil && !m.expired(&rec, t) {
+				rec.Accessed = t
+				if rec.Values == nil {
+					rec.Values = make(map[string]interface{})
+				}
+				return &Session{id: id, rec: rec}, nil
+			}
+			// expired or corrupted
+			if err = m.store.Delete(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		id:    id,
+		isNew: true,
+		rec: record{
+			Values:   make(map[string]interface{}),
+			Created:  t,
+			Accessed: t,
+		},
+	}, nil
+}
+
+func (m *manager) expired(rec *record, t time.Time) bool {
+	if m.config.IdleTimeout > 0 && t.Sub(rec.Accessed) > m.config.IdleTimeout {
+		return true
+	}
+	return m.config.AbsoluteTimeout > 0 && t.Sub(rec.Created) > m.config.AbsoluteTimeout
+}
+
+// ttl returns the duration before the session expires, 0 if it never expires.
+func (m *manager) ttl(rec *record) time.Duration {
+	var ttl time.Duration
+	if m.config.IdleTimeout > 0 {
+		ttl = m.config.IdleTimeout
+	}
+	if m.config.AbsoluteTimeout > 0 {
+		left := m.config.AbsoluteTimeout - rec.Accessed.Sub(rec.Created)
+		if ttl == 0 || left < ttl {
+			ttl = left
+		}
+	}
+	return ttl
+}
+
+// save saves the session and sets the cookie if the ID is new,
+// the new session is not saved until it is modified.
+func (m *manager) save(ctx *rester.RequestCtx, s *Session) error {
+	if s.oldID != "" {
+		if err := m.store.Delete(s.oldID); err != nil {
+			return err
+		}
+	}
+	if s.destroyed {
+		if !s.isNew {
+			if err := m.store.Delete(s.id); err != nil {
+				return err
+			}
+		}
+		m.setCookie(ctx, "", fasthttp.CookieExpireDelete)
+		return nil
+	}
+	if s.isNew && !s.modified {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&s.rec); err != nil {
+		return err
+	}
+	if err := m.store.Save(s.id, buf.Bytes(), m.ttl(&s.rec)); err != nil {
+		return err
+	}
+	if s.isNew || s.oldID != "" {
+		m.setCookie(ctx, s.id, time.Time{})
+	}
+	return nil
+}
+
+func (m *manager) setCookie(ctx *rester.RequestCtx, value string, expire time.Time) {
+	ck := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(ck)
+	ck.SetKey(m.config.CookieName)
+	ck.SetValue(value)
+	ck.SetPath(m.config.CookiePath)
+	ck.SetDomain(m.config.CookieDomain)
+	ck.SetSecure(m.config.CookieSecure)
+	ck.SetHTTPOnly(true)
+	ck.SetSameSite(m.config.CookieSameSite)
+	if !expire.IsZero() {
+		ck.SetExpire(expire)
+	}
+	ctx.Response.Header.SetCookie(ck)
+}
+
+// newID returns the random session ID of 256 bits.
+func newID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Session the session of the request.
+// NOTE:
+//  The values are encoded by encoding/gob, so the custom types should be registered by gob.Register;
+//  It is not safe for concurrent use.
+type Session struct {
+	id        string
+	oldID     string
+	rec       record
+	isNew     bool
+	modified  bool
+	destroyed bool
+}
+
+var _ rester.Session = new(Session)
+
+// ID returns the session ID.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Get returns the value of the key, or nil if it is not found.
+func (s *Session) Get(key string) interface{} {
+	return s.rec.Values[key]
+}
+
+// Set sets the value of the key.
+func (s *Session) Set(key string, value interface{}) {
+	s.rec.Values[key] = value
+	s.modified = true
+}
+
+// Delete deletes the value of the key.
+func (s *Session) Delete(key string) {
+	if _, ok := s.rec.Values[key]; ok {
+		delete(s.rec.Values, key)
+		s.modified = true
+	}
+}
+
+// Clear deletes all the values.
+func (s *Session) Clear() {
+	if len(s.rec.Values) > 0 || len(s.rec.Flashes) > 0 {
+		s.rec.Values = make(map[string]interface{})
+		s.rec.Flashes = nil
+		s.modified = true
+	}
+}
+
+// Regenerate changes the session ID and keeps the values,
+// the old ID is deleted from the store when the session is saved.
+// NOTE:
+//  It should be called when the privilege changes, such as login, to prevent session fixation.
+func (s *Session) Regenerate() {
+	id, err := newID()
+	if err != nil {
+		// it never happens unless the system random source is broken
+		panic(err)
+	}
+	if !s.isNew && s.oldID == "" {
+		s.oldID = s.id
+	}
+	s.id = id
+	s.modified = true
+}
+
+// Destroy deletes the session from the store and expires its cookie when the session is saved.
+func (s *Session) Destroy() {
+	s.destroyed = true
+}
+
+// AddFlash adds the flash message, which is kept until it is read by Flashes.
+func (s *Session) AddFlash(value interface{}) {
+	s.rec.Flashes = append(s.rec.Flashes, value)
+	s.modified = true
+}
+
+// Flashes returns and deletes the flash messages.
+func (s *Session) Flashes() []interface{} {
+	flashes := s.rec.Flashes
+	if len(flashes) > 0 {
+		s.rec.Flashes = nil
+		s.modified = true
+	}
+	return flashes
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/henrylee2cn/rester"
+)
+
+type CartCtl struct {
+	Middleware
+}
+
+func (ctl *CartCtl) GET() {
+	s := ctl.Session()
+	ctl.SetUserValue("count", s.Get("count"))
+	ctl.SetUserValue("flashes", s.Flashes())
+}
+
+func (ctl *CartCtl) POST() {
+	s := ctl.Session()
+	count, _ := s.Get("count").(int)
+	s.Set("count", count+1)
+	s.AddFlash("added")
+}
+
+func (ctl *CartCtl) PUT() {
+	ctl.Session().Regenerate()
+}
+
+func (ctl *CartCtl) DELETE() {
+	ctl.Session().Destroy()
+}
+
+type testClient struct {
+	t        *testing.T
+	handlers map[string]rester.RequestHandler
+	cookie   string
+}
+
+func newTestClient(t *testing.T, store Store, config *Config) *testClient {
+	mw := NewMiddleware(store, config)
+	handlers, err := rester.MakeHandlers(func() rester.Controller {
+		return &CartCtl{Middleware: *mw}
+	})
+	assert.NoError(t, err)
+	return &testClient{t: t, handlers: handlers}
+}
+
+func (c *testClient) do(method string) *fasthttp.RequestCtx {
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Init(new(fasthttp.Request), nil, nil)
+	if c.cookie != "" {
+		ctx.Request.Header.SetCookie("session_id", c.cookie)
+	}
+	c.handlers[method](ctx)
+	if v := ctx.Response.Header.PeekCookie("session_id"); v != nil {
+		var ck fasthttp.Cookie
+		assert.NoError(c.t, ck.ParseBytes(v))
+		assert.True(c.t, ck.HTTPOnly())
+		c.cookie = string(ck.Value())
+	}
+	return ctx
+}
+
+func TestMiddleware(t *testing.T) {
+	store := NewMemoryStore()
+	c := newTestClient(t, store, nil)
+
+	// the new session is not saved until it is modified
+	ctx := c.do("GET")
+	assert.Nil(t, ctx.UserValue("count"))
+	assert.Equal(t, "", c.cookie)
+	assert.Equal(t, 0, store.Len())
+
+	c.do("POST")
+	id := c.cookie
+	assert.NotEmpty(t, id)
+	c.do("POST")
+	assert.Equal(t, id, c.cookie)
+	ctx = c.do("GET")
+	assert.Equal(t, 2, ctx.UserValue("count"))
+	assert.Equal(t, []interface{}{"added", "added"}, ctx.UserValue("flashes"))
+	ctx = c.do("GET")
+	assert.Nil(t, ctx.UserValue("flashes"))
+
+	// the ID is changed and the values are kept
+	c.do("PUT")
+	assert.NotEqual(t, id, c.cookie)
+	data, _ := store.Load(id)
+	assert.Nil(t, data)
+	ctx = c.do("GET")
+	assert.Equal(t, 2, ctx.UserValue("count"))
+
+	// the cookie is expired
+	ctx = c.do("DELETE")
+	assert.Equal(t, "", c.cookie)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestTimeout(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+	c := newTestClient(t, NewMemoryStore(), &Config{IdleTimeout: time.Minute, AbsoluteTimeout: 3 * time.Minute})
+
+	c.do("POST")
+	id := c.cookie
+	for i := 0; i < 3; i++ {
+		clock = clock.Add(50 * time.Second)
+		ctx := c.do("GET")
+		assert.Equal(t, 1, ctx.UserValue("count"), i)
+	}
+	// absolute timeout
+	clock = clock.Add(40 * time.Second)
+	ctx := c.do("GET")
+	assert.Nil(t, ctx.UserValue("count"))
+
+	c.do("POST")
+	assert.NotEqual(t, id, c.cookie)
+	// idle timeout
+	clock = clock.Add(61 * time.Second)
+	ctx = c.do("GET")
+	assert.Nil(t, ctx.UserValue("count"))
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rester-session")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewFileStore(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	c := newTestClient(t, store, nil)
+	c.do("POST")
+	ctx := c.do("GET")
+	assert.Equal(t, 1, ctx.UserValue("count"))
+
+	assert.NoError(t, store.Save("a", []byte("x"), time.Millisecond))
+	assert.NoError(t, store.Save("b", []byte("y"), 0))
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, store.GC())
+	data, err := store.Load("a")
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	data, err = store.Load("b")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("y"), data)
+	assert.NoError(t, store.Delete("b"))
+	data, _ = store.Load("b")
+	assert.Nil(t, data)
+
+	assert.Error(t, store.Save("../a", []byte("x"), 0))
+	data, err = store.Load("../a")
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
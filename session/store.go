@@ -0,0 +1,210 @@
+package session
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Store the storage of the session data, such as the memory, files or Redis,
+// which must be safe for concurrent use.
+type Store interface {
+	// Load returns the data of the session, or nil if it is not found or expired.
+	Load(id string) ([]byte, error)
+	// Save saves the data of the session, which expires after ttl, or never if ttl is 0.
+	Save(id string, data []byte, ttl time.Duration) error
+	// Delete deletes the session, it is not an error if the session is not found.
+	Delete(id string) error
+}
+
+// MemoryStore the store in memory, the sessions are lost when the process exits.
+type MemoryStore struct {
+	lock      sync.RWMutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+func (e memoryEntry) expired(t time.Time) bool {
+	return !e.expires.IsZero() && !t.Before(e.expires)
+}
+
+// the interval of deleting the expired sessions in memory
+const sweepInterval = time.Minute
+
+var _ Store = new(MemoryStore)
+
+// NewMemoryStore creates the store in memory.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Load returns the data of the session, or nil if it is not found or expired.
+func (m *MemoryStore) Load(id string) ([]byte, error) {
+	m.lock.RLock()
+	e, ok := m.entries[id]
+	m.lock.RUnlock()
+	if !ok || e.expired(now()) {
+		return nil, nil
+	}
+	return e.data, nil
+}
+
+// Save saves the data of the session, which expires after ttl, or never if ttl is 0.
+func (m *MemoryStore) Save(id string, data []byte, ttl time.Duration) error {
+	t := now()
+	e := memoryEntry{data: append([]byte(nil), data...)}
+	if ttl > 0 {
+		e.expires = t.Add(ttl)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.entries[id] = e
+	if t.Sub(m.lastSweep) >= sweepInterval {
+		m.lastSweep = t
+		for k, e := range m.entries {
+			if e.expired(t) {
+				delete(m.entries, k)
+			}
+		}
+	}
+	return nil
+}
+
+// Delete deletes the session.
+func (m *MemoryStore) Delete(id string) error {
+	m.lock.Lock()
+	delete(m.entries, id)
+	m.lock.Unlock()
+	return nil
+}
+
+// Len returns the number of the sessions, including the expired ones not deleted yet.
+func (m *MemoryStore) Len() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return len(m.entries)
+}
+
+// FileStore the store of files, one file per session in the directory.
+// NOTE:
+//  The file begins with the expiration time in 8 bytes of Unix nanoseconds, 0 for never.
+type FileStore struct {
+	dir string
+}
+
+var _ Store = new(FileStore)
+
+var errInvalidID = errors.New("invalid session ID")
+
+// NewFileStore creates the store of files in the directory, which is created if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load returns the data of the session, or nil if it is not found or expired.
+func (f *FileStore) Load(id string) ([]byte, error) {
+	if !validID(id) {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(f.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(b) < 8 {
+		return nil, nil
+	}
+	if expires := int64(binary.BigEndian.Uint64(b)); expires != 0 && now().UnixNano() >= expires {
+		return nil, f.Delete(id)
+	}
+	return b[8:], nil
+}
+
+// Save saves the data of the session, which expires after ttl, or never if ttl is 0.
+func (f *FileStore) Save(id string, data []byte, ttl time.Duration) error {
+	if !validID(id) {
+		return errInvalidID
+	}
+	b := make([]byte, 8+len(data))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(b, uint64(now().Add(ttl).UnixNano()))
+	}
+	copy(b[8:], data)
+	// write the temporary file and rename it, so that the session is never read half written
+	tmp, err := ioutil.TempFile(f.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path(id))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Delete deletes the session.
+func (f *FileStore) Delete(id string) error {
+	if !validID(id) {
+		return nil
+	}
+	err := os.Remove(f.path(id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GC deletes the files of the expired sessions.
+func (f *FileStore) GC() error {
+	names, err := filepath.Glob(filepath.Join(f.dir, "*"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if id := filepath.Base(name); validID(id) {
+			if _, err = f.Load(id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FileStore) path(id string) string {
+	return filepath.Join(f.dir, id)
+}
+
+// validID reports whether the ID consists of the base64 URL characters,
+// so that it is safe as the file name.
+func validID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}